@@ -0,0 +1,168 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Attributes are the labels, weights and other values attached to the planned connection operations
+type Attributes map[string]interface{}
+
+const (
+	// LabelAttribute is the attribute key used by Label
+	LabelAttribute = "label"
+	// WeightAttribute is the attribute key used by Weight
+	WeightAttribute = "weight"
+)
+
+// Label returns attributes containing only the label
+func Label(label string) Attributes {
+	return Attributes{LabelAttribute: label}
+}
+
+// Weight returns attributes containing only the numeric weight
+func Weight(weight float64) Attributes {
+	return Attributes{WeightAttribute: weight}
+}
+
+// GetWeight returns the numeric weight of the attributes and false if there is none
+func (a Attributes) GetWeight() (float64, bool) {
+	weight, ok := a[WeightAttribute].(float64)
+	return weight, ok
+}
+
+// Clone returns a shallow copy of the attributes
+func (a Attributes) Clone() Attributes {
+	if a == nil {
+		return nil
+	}
+	clone := make(Attributes, len(a))
+	for k, v := range a {
+		clone[k] = v
+	}
+	return clone
+}
+
+// String prints the attributes sorted by their keys: '{label=x,weight=2}'
+func (a Attributes) String() string {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = fmt.Sprintf("%s=%v", k, a[k])
+	}
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+// AttributedOperator can be implemented instead of Operator, when the attributes of the connections are needed
+type AttributedOperator interface {
+	Operator
+	// EvaluateWithAttributes should connect connectable a to connectable: a -> b using the attributes,
+	// which may be nil. It is called instead of Evaluate
+	EvaluateWithAttributes(a Connectable, b Connectable, attributes Attributes) error
+}
+
+// AttributeMerger decides the attributes, when the same connection operation is added with different attributes
+type AttributeMerger interface {
+	// Merge returns the attributes of the united operation. The existing attributes come from the left side of the '+'
+	Merge(existing Attributes, added Attributes) Attributes
+}
+
+// AttributeMergerFunc adapts a function to the AttributeMerger interface
+type AttributeMergerFunc func(existing Attributes, added Attributes) Attributes
+
+// Merge calls the function
+func (f AttributeMergerFunc) Merge(existing Attributes, added Attributes) Attributes {
+	return f(existing, added)
+}
+
+var (
+	// MergeKeepExisting keeps the attributes of the left side of the '+'
+	MergeKeepExisting AttributeMerger = AttributeMergerFunc(func(existing Attributes, added Attributes) Attributes {
+		return existing
+	})
+	// MergeKeepAdded takes the attributes of the right side of the '+'
+	MergeKeepAdded AttributeMerger = AttributeMergerFunc(func(existing Attributes, added Attributes) Attributes {
+		return added
+	})
+	// MergeUnion unites the attributes. The right side of the '+' wins on the conflicting keys. This is the default policy
+	MergeUnion AttributeMerger = AttributeMergerFunc(mergeUnion)
+	// MergeSumWeights unites the attributes like MergeUnion, but sums the weights
+	MergeSumWeights AttributeMerger = AttributeMergerFunc(func(existing Attributes, added Attributes) Attributes {
+		merged := mergeUnion(existing, added)
+		first, hasFirst := existing.GetWeight()
+		second, hasSecond := added.GetWeight()
+		if hasFirst && hasSecond {
+			merged[WeightAttribute] = first + second
+		}
+		return merged
+	})
+)
+
+// NewAttributedOperation creates a new FreezedOperation instance carrying the attributes using the IdentityById policy
+func NewAttributedOperation(operator Operator, source Connectable, sink Connectable, attributes Attributes) FreezedOperation {
+	return &freezedOperation{
+		Source:     source,
+		Sink:       sink,
+		Operator:   operator,
+		Equaler:    IdentityById,
+		Attributes: attributes.Clone()}
+}
+
+// GetAttributes returns the attributes of the operation or nil, if it has none
+func GetAttributes(op FreezedOperation) Attributes {
+	attributed, ok := op.(interface{ GetAttributes() Attributes })
+	if !ok {
+		return nil
+	}
+	return attributed.GetAttributes()
+}
+
+// implementation details
+
+func mergeUnion(existing Attributes, added Attributes) Attributes {
+	if existing == nil && added == nil {
+		return nil
+	}
+	merged := existing.Clone()
+	if merged == nil {
+		merged = make(Attributes, len(added))
+	}
+	for k, v := range added {
+		merged[k] = v
+	}
+	return merged
+}
+
+// evaluateOperator passes the attributes to the operators implementing AttributedOperator
+func evaluateOperator(operator Operator, a Connectable, b Connectable, attributes Attributes) error {
+	attributed, ok := operator.(AttributedOperator)
+	if ok {
+		return attributed.EvaluateWithAttributes(a, b, attributes)
+	}
+	return operator.Evaluate(a, b)
+}
+
+// withAttributes returns the operation with the given attributes
+func withAttributes(op FreezedOperation, attributes Attributes) FreezedOperation {
+	if len(attributes) == 0 && len(GetAttributes(op)) == 0 {
+		return op
+	}
+	freezed, ok := op.(*freezedOperation)
+	if !ok {
+		return NewAttributedOperation(op.GetOperator(), op.GetSource(), op.GetSink(), attributes)
+	}
+	clone := *freezed
+	clone.Attributes = attributes.Clone()
+	return &clone
+}
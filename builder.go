@@ -0,0 +1,232 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+)
+
+// Builder is a fluent equation builder which validates its input. The first found error is recorded
+// and returned from Build, the later operations are skipped
+type Builder interface {
+	// Add is the '+' of the equation operations
+	Add(another Builder) Builder
+	// Discard is the '-' of the equation operations
+	Discard(another Builder) Builder
+	// Connect is the '->' of the equation operations
+	Connect(another Builder) Builder
+	// ConnectWith is the '->' of the equation operations using the given operator for the new connections
+	ConnectWith(operator Operator, another Builder) Builder
+	// Build returns the built term or the first error found
+	Build() (EquationTerm, error)
+	// String prints the built expression in human readable form. Do not use for serialization.
+	String() string
+}
+
+// BuilderFactory makes the Builder instances the same way as EquationFactory makes the terms
+type BuilderFactory interface {
+	// returns the Idetitity term builder
+	I() Builder
+	// returns the terminatOr term builder
+	O() Builder
+	// wraps your connectable into a builder, nil connectables are errors
+	W(c Connectable) Builder
+	// returns a free variable placeholder term builder
+	Var(name string) Builder
+	// wraps your ported connectable into a builder, nil connectables are errors
+	P(c PortedConnectable) Builder
+	// wraps a single named port of your connectable into a builder, unknown ports are errors
+	Port(c PortedConnectable, name string) Builder
+	// wraps an existing term into a builder
+	T(term EquationTerm) Builder
+}
+
+// BuildError is the error found by a Builder
+type BuildError struct {
+	// Path is the printed expression where the error was found
+	Path string
+	// Err is the found error
+	Err error
+}
+
+// Error describes the error and where it was found
+func (e *BuildError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// NewBuilderFactory creates a new BuilderFactory using the given EquationFactory
+func NewBuilderFactory(factory EquationFactory) BuilderFactory {
+	return &builderFactory{Factory: factory}
+}
+
+// implementation details
+
+type builderFactory struct {
+	Factory EquationFactory
+}
+
+func (f *builderFactory) I() Builder {
+	return f.T(f.Factory.I())
+}
+
+func (f *builderFactory) O() Builder {
+	return f.T(f.Factory.O())
+}
+
+func (f *builderFactory) W(c Connectable) Builder {
+	if c == nil {
+		return &builder{Text: "<nil>", Err: &BuildError{Path: "<nil>", Err: fmt.Errorf("nil connectable")}}
+	}
+	return f.T(f.Factory.W(c))
+}
+
+func (f *builderFactory) Var(name string) Builder {
+	return f.T(f.Factory.Var(name))
+}
+
+func (f *builderFactory) P(c PortedConnectable) Builder {
+	if c == nil {
+		return &builder{Text: "<nil>", Err: &BuildError{Path: "<nil>", Err: fmt.Errorf("nil connectable")}}
+	}
+	return f.T(f.Factory.P(c))
+}
+
+func (f *builderFactory) Port(c PortedConnectable, name string) Builder {
+	if c == nil {
+		return &builder{Text: "<nil>", Err: &BuildError{Path: "<nil>", Err: fmt.Errorf("nil connectable")}}
+	}
+	_, err := lookupPort(c, name)
+	if err != nil {
+		text := c.GetId() + "." + name
+		return &builder{Text: text, Err: &BuildError{Path: text, Err: err}}
+	}
+	return f.T(f.Factory.Port(c, name))
+}
+
+func (f *builderFactory) T(term EquationTerm) Builder {
+	if term == nil {
+		return &builder{Text: "<nil>", Err: &BuildError{Path: "<nil>", Err: fmt.Errorf("nil term")}}
+	}
+	return &builder{Term: term, Text: term.String()}
+}
+
+type builder struct {
+	Term EquationTerm
+	Text string
+	Err  error
+}
+
+func (b *builder) Add(another Builder) Builder {
+	return b.combine(another, ADD, nil)
+}
+
+func (b *builder) Discard(another Builder) Builder {
+	return b.combine(another, DISCARD, nil)
+}
+
+func (b *builder) Connect(another Builder) Builder {
+	return b.combine(another, ARROW, nil)
+}
+
+func (b *builder) ConnectWith(operator Operator, another Builder) Builder {
+	if operator == nil {
+		return b.fail(another, ARROW, nil, fmt.Errorf("nil operator"))
+	}
+	return b.combine(another, ARROW, operator)
+}
+
+func (b *builder) Build() (EquationTerm, error) {
+	if b.Err != nil {
+		return nil, b.Err
+	}
+	return b.Term, nil
+}
+
+func (b *builder) String() string {
+	return b.Text
+}
+
+func (b *builder) combine(another Builder, operation Operation, operator Operator) Builder {
+	if another == nil {
+		return b.fail(nil, operation, operator, fmt.Errorf("nil builder"))
+	}
+	anotherTerm, err := another.Build()
+	if b.Err != nil {
+		return b.fail(another, operation, operator, b.Err)
+	}
+	if err != nil {
+		return b.fail(another, operation, operator, err)
+	}
+
+	err = checkCompatible(b.Term, anotherTerm)
+	if err == nil {
+		err = checkConnectableIds(b.Term, anotherTerm)
+	}
+	if err != nil {
+		return b.fail(another, operation, operator, err)
+	}
+
+	if operator == nil {
+		operator = b.Term.GetOperator()
+	}
+
+	var term EquationTerm
+	switch operation {
+	case ADD:
+		term = b.Term.Add(anotherTerm)
+	case DISCARD:
+		term = b.Term.Discard(anotherTerm)
+	case ARROW:
+		term = b.Term.ConnectWith(operator, anotherTerm)
+		err = ValidatePorts(term)
+		if err != nil {
+			return b.fail(another, operation, operator, err)
+		}
+	}
+	return &builder{
+		Term: term,
+		Text: formatOperation(b.Text, operation, operator, b.Term.GetOperator(), nil, another.String())}
+}
+
+// fail returns a builder containing the first error. Errors found by the other builders keep their path
+func (b *builder) fail(another Builder, operation Operation, operator Operator, err error) Builder {
+	anotherText := "<nil>"
+	if another != nil {
+		anotherText = another.String()
+	}
+	text := fmt.Sprintf("(%s) %s (%s)", b.Text, O2S(operation), anotherText)
+	if operator != nil && b.Term != nil {
+		text = formatOperation(b.Text, operation, operator, b.Term.GetOperator(), nil, anotherText)
+	}
+
+	_, isBuildError := err.(*BuildError)
+	if !isBuildError {
+		err = &BuildError{Path: text, Err: err}
+	}
+	return &builder{Text: text, Err: err}
+}
+
+// checkConnectableIds returns an error, if the categories contain different objects which the identity policy
+// considers the same connectable: they would silently replace each other on the sets. Only the sets and
+// the operations are checked, because the builder has already checked the terms they were built from
+func checkConnectableIds(c Category, another Category) error {
+	policy := policyOf(c).Identity
+	connectables := make(map[string][]Connectable)
+	collectConnectables(c, connectables)
+
+	found := make(map[string][]Connectable)
+	collectConnectables(another, found)
+	for id, anotherConnectables := range found {
+		for _, connectable := range connectables[id] {
+			for _, anotherConnectable := range anotherConnectables {
+				if policy.Equal(connectable, anotherConnectable) && !sameObject(connectable, anotherConnectable) {
+					return fmt.Errorf("Connectable id '%s' is used by two different objects", id)
+				}
+			}
+		}
+	}
+	return nil
+}
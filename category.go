@@ -47,7 +47,12 @@ func (c *categoryImpl) IsIdentity() bool {
 }
 
 func (c *categoryImpl) Evaluate() error {
-	for _, f := range c.Operations.AsArray() {
+	operations := flattenOperations(c)
+	err := validateOperationPorts(operations)
+	if err != nil {
+		return err
+	}
+	for _, f := range operations.AsArray() {
 		err := f.Evaluate()
 		if err != nil {
 			return err
@@ -58,7 +63,12 @@ func (c *categoryImpl) Evaluate() error {
 }
 
 func (c *categoryImpl) EvaluateSorted() error {
-	for _, f := range c.Operations.AsSortedArray() {
+	operations := flattenOperations(c)
+	err := validateOperationPorts(operations)
+	if err != nil {
+		return err
+	}
+	for _, f := range operations.AsSortedArray() {
 		err := f.Evaluate()
 		if err != nil {
 			return err
@@ -68,6 +78,18 @@ func (c *categoryImpl) EvaluateSorted() error {
 	return nil
 }
 
+func (c *categoryImpl) Teardown() error {
+	operations := flattenOperations(c).AsSortedArray()
+	for i := len(operations) - 1; i >= 0; i-- {
+		err := disconnect(operations[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (c *categoryImpl) String() string {
 	return c.stringImpl(c)
 }
@@ -20,6 +20,12 @@ type Operator interface {
 	GetId() string
 }
 
+// Disconnector can be implemented by the Operator in order to undo the connections on Teardown
+type Disconnector interface {
+	// Disconnect should remove the connection made by Evaluate: a -> b
+	Disconnect(a Connectable, b Connectable) error
+}
+
 // Category is plainly a container for already planned connection operations, sources and sinks
 type Category interface {
 	// GetSources returns the sources which are outputs from this category to the next one
@@ -40,6 +46,9 @@ type Category interface {
 	Evaluate() error
 	// EvaluateSorted calls the operator to connect the all the planned connections in alphabetical order
 	EvaluateSorted() error
+	// Teardown calls the operators implementing Disconnector to disconnect the all the planned connections
+	// in the reverse of the EvaluateSorted order. The connections of the other operators are skipped
+	Teardown() error
 	// String prints the category in human readable form. Do not use for serialization.
 	String() string
 }
@@ -55,10 +64,16 @@ type EquationTerm interface {
 	Discard(category Category) EquationTerm
 	// Connect is the '->' of the equation operations
 	Connect(category Category) EquationTerm
+	// ConnectWith is the '->' of the equation operations using the given operator for the new connections
+	// instead of the operator of this term
+	ConnectWith(operator Operator, category Category) EquationTerm
+	// ConnectWithAttributes is the '->' of the equation operations attaching the attributes to the new connections
+	ConnectWithAttributes(attributes Attributes, category Category) EquationTerm
 }
 
 // EquationFactory is finally the place where category equations can be made from
-// Please do not mix equations done with two different operators, because they might not work well together
+// Please do not mix equations done with two different operators, because they might not work well together.
+// Use ConnectWith when different kinds of connections are needed on the same equation
 type EquationFactory interface {
 	// returns the Idetitity term
 	I() EquationTerm
@@ -66,29 +81,101 @@ type EquationFactory interface {
 	O() EquationTerm
 	// wraps your connectable into a equation term
 	W(c Connectable) EquationTerm
+	// returns a free variable placeholder term to be bound later
+	Var(name string) EquationTerm
+	// wraps your ported connectable into a equation term having the output ports as sources and the input ports as sinks
+	P(c PortedConnectable) EquationTerm
+	// wraps a single named port of your connectable into a equation term. Panics on unknown names,
+	// use TryPort or the Builder to get the error instead
+	Port(c PortedConnectable, name string) EquationTerm
+	// returns the operator used on the equations
+	GetOperator() Operator
+}
+
+// FactoryOption is used to select optional behaviour for the EquationFactory
+type FactoryOption func(factory *equationFactory)
+
+// MustMatchOperators makes Add, Discard and Connect to panic with *OperatorMismatchError, when
+// the operators are not compatible and either of the operands is made by the factory or built from
+// such terms. Use TryAdd, TryDiscard and TryConnect to get the error instead of the panic
+var MustMatchOperators FactoryOption = func(factory *equationFactory) {
+	factory.Strict = true
+}
+
+// IdentityPolicy makes the terms of the factory and the terms built from them to use the policy
+// on their sets instead of IdentityById. The terms combined from the terms of different policies
+// have the policy of the left operand
+func IdentityPolicy(policy Equaler) FactoryOption {
+	return func(factory *equationFactory) {
+		if policy != nil {
+			factory.Policy.Identity = policy
+		}
+	}
+}
+
+// AttributeMergePolicy makes the operation sets of the factory terms and the terms built from them
+// to merge the attributes by the policy instead of MergeUnion. The terms combined from the terms of
+// different policies have the policy of the left operand
+func AttributeMergePolicy(policy AttributeMerger) FactoryOption {
+	return func(factory *equationFactory) {
+		if policy != nil {
+			factory.Policy.Merger = policy
+		}
+	}
 }
 
 // NewEquationFactory creates a new Equation factory
 //
 // Please do not mix equations done with two different operators, because they might not work well together
-func NewEquationFactory(operator Operator) EquationFactory {
-	return &equationFactory{Operator: operator}
+func NewEquationFactory(operator Operator, options ...FactoryOption) EquationFactory {
+	factory := &equationFactory{Operator: operator, Policy: defaultPolicy}
+	for _, option := range options {
+		option(factory)
+	}
+	return factory
 }
 
 // Implementation details
 
 type equationFactory struct {
 	Operator Operator
+	Strict   bool
+	Policy   setPolicy
+}
+
+// leaf applies the options of the factory to the new term
+func (p *equationFactory) leaf(term EquationTerm) EquationTerm {
+	return markStrict(withPolicy(term, p.Policy), p.Strict)
 }
 
 func (p *equationFactory) I() EquationTerm {
-	return NewIdentityTerm(p.Operator)
+	return p.leaf(NewIdentityTerm(p.Operator))
 }
 
 func (p *equationFactory) O() EquationTerm {
-	return NewZeroTerm(p.Operator)
+	return p.leaf(NewZeroTerm(p.Operator))
 }
 
 func (p *equationFactory) W(c Connectable) EquationTerm {
-	return NewWrapperTerm(p.Operator, c)
+	return p.leaf(NewWrapperTerm(p.Operator, c))
+}
+
+func (p *equationFactory) Var(name string) EquationTerm {
+	return p.leaf(NewVariableTerm(p.Operator, name))
+}
+
+func (p *equationFactory) P(c PortedConnectable) EquationTerm {
+	return p.leaf(NewPortedWrapperTerm(p.Operator, c))
+}
+
+func (p *equationFactory) Port(c PortedConnectable, name string) EquationTerm {
+	port, err := lookupPort(c, name)
+	if err != nil {
+		panic(err)
+	}
+	return p.leaf(NewPortTerm(p.Operator, port))
+}
+
+func (p *equationFactory) GetOperator() Operator {
+	return p.Operator
 }
@@ -0,0 +1,95 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+type attributeRecorder struct {
+	Attributes map[string]category.Attributes
+}
+
+func (r *attributeRecorder) Evaluate(a category.Connectable, b category.Connectable) error {
+	return r.EvaluateWithAttributes(a, b, nil)
+}
+
+func (r *attributeRecorder) EvaluateWithAttributes(a category.Connectable, b category.Connectable, attributes category.Attributes) error {
+	r.Attributes[a.GetId()+" -> "+b.GetId()] = attributes
+	return nil
+}
+
+func (r *attributeRecorder) GetId() string {
+	return "attributes"
+}
+
+func TestConnectWithAttributes(t *testing.T) {
+	recorder := &attributeRecorder{Attributes: make(map[string]category.Attributes)}
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	first := a.ConnectWithAttributes(category.Weight(2), b).ConnectWithAttributes(category.Label("data"), c)
+	t.Log(first.String())
+	if first.String() != "((a) *{weight=2} (b)) *{label=data} (c)" {
+		t.Fatalf("attribute print problem")
+	}
+
+	err := first.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	weight, ok := recorder.Attributes["a -> b"].GetWeight()
+	if !ok || weight != 2 {
+		t.Fatalf("weight evaluation problem: %v", recorder.Attributes)
+	}
+	if recorder.Attributes["b -> c"][category.LabelAttribute] != "data" {
+		t.Fatalf("label evaluation problem: %v", recorder.Attributes)
+	}
+
+	if !first.Equals(a.Connect(b).Connect(c)) {
+		t.Fatalf("attributes should not affect equality")
+	}
+
+	reversed := category.Reverse(first)
+	for _, op := range reversed.GetOperations().AsArray() {
+		if op.GetSource().GetId() == "b" && category.GetAttributes(op).String() != "{weight=2}" {
+			t.Fatalf("reverse attribute problem: %s", category.GetAttributes(op))
+		}
+	}
+}
+
+func TestAttributeMergePolicy(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"), category.AttributeMergePolicy(category.MergeSumWeights))
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+
+	first := a.ConnectWithAttributes(category.Weight(2), b).Add(a.ConnectWithAttributes(category.Weight(3), b))
+	ops := first.GetOperations().AsArray()
+	if len(ops) != 1 {
+		t.Fatalf("operation count problem: %d", len(ops))
+	}
+	weight, _ := category.GetAttributes(ops[0]).GetWeight()
+	if weight != 5 {
+		t.Fatalf("weight merge problem: %v", weight)
+	}
+
+	H := category.NewEquationFactory(NewConnectionRecorder("data"), category.AttributeMergePolicy(category.MergeKeepExisting))
+	a = H.W(NewConnectable("a"))
+	b = H.W(NewConnectable("b"))
+	second := a.ConnectWithAttributes(category.Label("x"), b).Add(a.ConnectWithAttributes(category.Label("y"), b))
+	if category.GetAttributes(second.GetOperations().AsArray()[0]).String() != "{label=x}" {
+		t.Fatalf("keep existing merge problem")
+	}
+
+	third := first.Add(first)
+	weight, _ = category.GetAttributes(third.GetOperations().AsArray()[0]).GetWeight()
+	if weight != 10 {
+		t.Fatalf("the policy is not carried by the built terms: %v", weight)
+	}
+}
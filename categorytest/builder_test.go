@@ -0,0 +1,64 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+	B := category.NewBuilderFactory(G)
+
+	a := NewConnectable("a")
+	b := NewConnectable("b")
+	c := NewConnectable("c")
+
+	term, err := B.W(a).Add(B.W(b)).Connect(B.W(c).Add(B.I())).Build()
+	if err != nil {
+		t.Fatalf("build problem: %s", err)
+	}
+	expected := G.W(a).Add(G.W(b)).Connect(G.W(c).Add(G.I()))
+	if !term.Equals(expected) || term.String() != expected.String() {
+		t.Fatalf("built term problem")
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+	H := category.NewEquationFactory(NewConnectionRecorder("other"))
+	B := category.NewBuilderFactory(G)
+
+	a := NewConnectable("a")
+	b := NewConnectable("b")
+
+	_, err := B.W(a).Add(B.W(nil)).Connect(B.W(b)).Build()
+	t.Log(err)
+	buildError, ok := err.(*category.BuildError)
+	if !ok || buildError.Path != "<nil>" {
+		t.Fatalf("nil connectable problem: %v", err)
+	}
+
+	_, err = B.W(a).Connect(B.W(b)).Add(B.W(NewConnectable("a"))).Build()
+	t.Log(err)
+	buildError, ok = err.(*category.BuildError)
+	if !ok || buildError.Path != "((a) * (b)) + (a)" {
+		t.Fatalf("duplicate id problem: %v", err)
+	}
+
+	_, err = B.W(a).Connect(B.T(H.W(b))).Add(B.W(b)).Build()
+	t.Log(err)
+	buildError, ok = err.(*category.BuildError)
+	if !ok {
+		t.Fatalf("operator mismatch problem: %v", err)
+	}
+	_, ok = buildError.Err.(*category.OperatorMismatchError)
+	if !ok || buildError.Path != "(a) * (b)" {
+		t.Fatalf("operator mismatch problem: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestStronglyConnectedComponents(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+	e := G.W(NewConnectable("e"))
+
+	loops := a.Connect(b).Connect(c).Connect(b).Add(c.Connect(d).Connect(e).Connect(d))
+	found := []string{}
+	for _, members := range graph.StronglyConnectedComponents(loops) {
+		found = append(found, (&graph.Component{Members: members}).GetId())
+	}
+	t.Log(found)
+	if strings.Join(found, " ") != "a {b,c} {d,e}" {
+		t.Fatalf("component problem: %v", found)
+	}
+}
+
+func TestCondense(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	loops := a.Connect(b).Connect(c).Connect(b).Connect(d)
+	condensed := graph.Condense(loops)
+	t.Log(condensed.String())
+	if !graph.IsAcyclic(condensed) {
+		t.Fatalf("condensation should be acyclic")
+	}
+
+	err := condensed.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	sort.Strings(recorder.Connections)
+	if strings.Join(recorder.Connections, ",") != "a -> {b,c},{b,c} -> d" {
+		t.Fatalf("condensed evaluation problem: %v", recorder.Connections)
+	}
+	sinks := condensed.GetSinks().AsArray()
+	if len(sinks) != 1 || sinks[0].GetId() != "a" || condensed.GetSources().AsArray()[0].GetId() != "d" {
+		t.Fatalf("condensed roles problem")
+	}
+}
+
+func TestCondenseParallelEdges(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	left := G.W(NewConnectable("l0"))
+	right := G.W(NewConnectable("r0"))
+	for i := 1; i < 100; i++ {
+		left = left.Add(G.W(NewConnectable(fmt.Sprintf("l%d", i))))
+		right = right.Add(G.W(NewConnectable(fmt.Sprintf("r%d", i))))
+	}
+	loops := left.Connect(left).ConnectWithAttributes(category.Label("x"), right.Connect(right))
+
+	operations := graph.Condense(loops).GetOperations().AsArray()
+	if len(operations) != 1 || category.GetAttributes(operations[0]).String() != "{label=x}" {
+		t.Fatalf("parallel edges should be merged: %v", operations)
+	}
+}
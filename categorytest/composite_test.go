@@ -0,0 +1,74 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestEncapsulate(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	x := G.W(NewConnectable("x"))
+	y := G.W(NewConnectable("y"))
+
+	inner := a.Connect(b.Add(c))
+	box := category.Encapsulate("box", inner)
+	first := x.Connect(box).Connect(y)
+	t.Log(first.String())
+	if first.String() != "((x) * (box)) * (y)" {
+		t.Fatalf("composite print problem")
+	}
+	if len(first.GetOperations().AsArray()) != 2 {
+		t.Fatalf("composite should be a single node")
+	}
+
+	err := first.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	sort.Strings(recorder.Connections)
+	if strings.Join(recorder.Connections, ",") != "a -> b,a -> c,b -> y,c -> y,x -> a" {
+		t.Fatalf("composite evaluation problem: %v", recorder.Connections)
+	}
+
+	expanded := category.ExpandComposites(first)
+	if !expanded.Equals(x.Connect(inner).Connect(y)) {
+		t.Fatalf("expand problem: %s", expanded)
+	}
+	if !category.ExpandComposites(category.Reverse(first)).Equals(category.Reverse(expanded)) {
+		t.Fatalf("composite reverse problem")
+	}
+}
+
+func TestNestedComposites(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	inner := category.Encapsulate("inner", a.Connect(b))
+	outer := category.Encapsulate("outer", inner.Connect(c))
+
+	err := outer.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	if strings.Join(recorder.Connections, ",") != "a -> b,b -> c" {
+		t.Fatalf("nested evaluation problem: %v", recorder.Connections)
+	}
+	if !category.ExpandComposites(outer).Equals(a.Connect(b).Connect(c)) {
+		t.Fatalf("nested expand problem")
+	}
+}
@@ -0,0 +1,177 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestCompositeOperatorModes(t *testing.T) {
+	newOperators := func() (*wiring, *wiring, *wiring) {
+		route := &wiring{Id: "route", Wired: make(map[string]bool)}
+		socket := &wiring{Id: "socket", Wired: make(map[string]bool)}
+		metric := &wiring{Id: "metric", Wired: make(map[string]bool)}
+		return route, socket, metric
+	}
+	a := NewConnectable("a")
+	b := NewConnectable("b")
+
+	route, socket, metric := newOperators()
+	all := category.NewCompositeOperator("all", category.AllOrNothing, route, socket, metric, &failing{})
+	err := all.Evaluate(a, b)
+	t.Log(err)
+	if err == nil || len(route.Wired) != 0 || len(socket.Wired) != 0 || len(metric.Wired) != 0 {
+		t.Fatalf("all or nothing rollback problem: %v", err)
+	}
+	if strings.Join(route.Log, ",") != "+a -> b,-a -> b" {
+		t.Fatalf("rollback order problem: %v", route.Log)
+	}
+
+	route, socket, metric = newOperators()
+	best := category.NewCompositeOperator("best", category.BestEffort, route, &failing{}, socket, metric)
+	err = best.Evaluate(a, b)
+	if err != nil {
+		t.Fatalf("best effort error problem: %v", err)
+	}
+	if !route.Wired["a -> b"] || !socket.Wired["a -> b"] || !metric.Wired["a -> b"] {
+		t.Fatalf("best effort evaluation problem")
+	}
+
+	route, socket, metric = newOperators()
+	first := category.NewCompositeOperator("first", category.FirstSuccess, &failing{}, route, socket, metric)
+	err = first.Evaluate(a, b)
+	if err != nil || !route.Wired["a -> b"] || socket.Wired["a -> b"] || metric.Wired["a -> b"] {
+		t.Fatalf("first success problem: %v", err)
+	}
+}
+
+func TestCompositeOperatorEquation(t *testing.T) {
+	route := &wiring{Id: "route", Wired: make(map[string]bool)}
+	metric := &wiring{Id: "metric", Wired: make(map[string]bool)}
+	G := category.NewEquationFactory(category.NewCompositeOperator("both", category.AllOrNothing, route, metric))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	first := a.Connect(b.Add(c))
+
+	err := first.EvaluateSorted()
+	if err != nil || len(route.Wired) != 2 || len(metric.Wired) != 2 {
+		t.Fatalf("composite operator evaluation problem: %v", err)
+	}
+	err = first.Teardown()
+	if err != nil || len(route.Wired) != 0 || len(metric.Wired) != 0 {
+		t.Fatalf("composite operator teardown problem: %v", err)
+	}
+}
+
+type failing struct{}
+
+func (f *failing) Evaluate(a category.Connectable, b category.Connectable) error {
+	return fmt.Errorf("failing %s -> %s", a.GetId(), b.GetId())
+}
+
+func (f *failing) GetId() string {
+	return "failing"
+}
+
+func TestCompositeOperatorDisconnectModes(t *testing.T) {
+	a := NewConnectable("a")
+	b := NewConnectable("b")
+
+	route := &connectedOnly{wiring{Id: "route", Wired: make(map[string]bool)}}
+	socket := &connectedOnly{wiring{Id: "socket", Wired: make(map[string]bool)}}
+	first := category.NewCompositeOperator("first", category.FirstSuccess, route, socket)
+	err := first.Evaluate(a, b)
+	if err == nil {
+		err = first.Disconnect(a, b)
+	}
+	if err != nil || len(route.Wired) != 0 || len(socket.Log) != 0 {
+		t.Fatalf("first success disconnect problem: %v %v", err, socket.Log)
+	}
+
+	route = &connectedOnly{wiring{Id: "route", Wired: make(map[string]bool)}}
+	socket = &connectedOnly{wiring{Id: "socket", Wired: make(map[string]bool)}}
+	best := category.NewCompositeOperator("best", category.BestEffort, &failing{}, route, socket)
+	err = socket.Evaluate(a, b)
+	if err == nil {
+		err = best.Disconnect(a, b)
+	}
+	if err != nil || len(socket.Wired) != 0 {
+		t.Fatalf("best effort disconnect problem: %v", err)
+	}
+	err = best.Disconnect(a, b)
+	t.Log(err)
+	if err == nil || len(err.(*category.CompositeOperatorError).Errors) != 2 {
+		t.Fatalf("best effort disconnect error problem: %v", err)
+	}
+
+	route = &connectedOnly{wiring{Id: "route", Wired: make(map[string]bool)}}
+	socket = &connectedOnly{wiring{Id: "socket", Wired: make(map[string]bool)}}
+	all := category.NewCompositeOperator("all", category.AllOrNothing, route, socket)
+	err = route.Evaluate(a, b)
+	if err == nil {
+		err = all.Disconnect(a, b)
+	}
+	t.Log(err)
+	if err == nil || len(err.(*category.CompositeOperatorError).Errors) != 1 || len(route.Wired) != 0 {
+		t.Fatalf("all or nothing disconnect problem: %v", err)
+	}
+}
+
+// connectedOnly refuses to disconnect the connections it has not made
+type connectedOnly struct {
+	wiring
+}
+
+func (w *connectedOnly) Disconnect(a category.Connectable, b category.Connectable) error {
+	key := a.GetId() + " -> " + b.GetId()
+	if !w.Wired[key] {
+		return fmt.Errorf("%s: %s is not connected", w.Id, key)
+	}
+	return w.wiring.Disconnect(a, b)
+}
+
+func TestCompositeOperatorModeEquations(t *testing.T) {
+	route := &wiring{Id: "route", Wired: make(map[string]bool)}
+	G := category.NewEquationFactory(category.NewCompositeOperator("best", category.BestEffort, &failing{}, route))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	err := a.Connect(b.Add(c)).EvaluateSorted()
+	if err != nil || len(route.Wired) != 2 {
+		t.Fatalf("best effort should evaluate all the connections: %v %v", err, route.Wired)
+	}
+	err = category.NewCompositeOperator("none", category.BestEffort, &failing{}, &failing{}).Evaluate(NewConnectable("a"), NewConnectable("b"))
+	if err == nil || len(err.(*category.CompositeOperatorError).Errors) != 2 {
+		t.Fatalf("best effort should fail when all the operators fail: %v", err)
+	}
+
+	broken := &brokenWiring{wiring{Id: "broken", Wired: make(map[string]bool)}}
+	route = &wiring{Id: "route", Wired: make(map[string]bool)}
+	G = category.NewEquationFactory(category.NewCompositeOperator("first", category.FirstSuccess, broken, route))
+	first := G.W(NewConnectable("a")).Connect(G.W(NewConnectable("b")))
+	err = first.EvaluateSorted()
+	if err != nil || !route.Wired["a -> b"] {
+		t.Fatalf("first success evaluation problem: %v", err)
+	}
+	err = first.Teardown()
+	if err != nil || len(route.Wired) != 0 {
+		t.Fatalf("first success teardown should disconnect the connected operator: %v %v", err, route.Wired)
+	}
+}
+
+// brokenWiring fails to connect, but accepts disconnecting anything
+type brokenWiring struct {
+	wiring
+}
+
+func (w *brokenWiring) Evaluate(a category.Connectable, b category.Connectable) error {
+	return fmt.Errorf("%s: can not connect %s -> %s", w.Id, a.GetId(), b.GetId())
+}
@@ -0,0 +1,108 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"fmt"
+	"testing"
+)
+
+func TestFindCycles(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	acyclic := a.Connect(b.Add(c)).Connect(d)
+	if !graph.IsAcyclic(acyclic) || len(graph.FindCycles(acyclic)) != 0 {
+		t.Fatalf("acyclic problem")
+	}
+
+	cyclic := a.Connect(b).Connect(a).Add(b.Connect(c).Connect(b)).Add(d.Connect(d))
+	if graph.IsAcyclic(cyclic) {
+		t.Fatalf("cyclic problem")
+	}
+	found := []string{}
+	for _, cycle := range graph.FindCycles(cyclic) {
+		found = append(found, graph.FormatCycle(cycle))
+	}
+	t.Log(found)
+	expected := []string{"a -> b -> a", "b -> c -> b", "d -> d"}
+	if len(found) != len(expected) {
+		t.Fatalf("cycle count problem: %v", found)
+	}
+	for i := range expected {
+		if found[i] != expected[i] {
+			t.Fatalf("cycle problem: %v", found)
+		}
+	}
+
+	triangle := a.Connect(b).Connect(c).Connect(a).Add(a.Connect(c))
+	cycles := graph.FindCycles(triangle)
+	if len(cycles) != 2 || graph.FormatCycle(cycles[0]) != "a -> b -> c -> a" || graph.FormatCycle(cycles[1]) != "a -> c -> a" {
+		t.Fatalf("triangle problem: %v", cycles)
+	}
+}
+
+func TestRefuseCycles(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+
+	err := graph.Evaluate(a.Connect(b).Connect(a), graph.RefuseCycles)
+	t.Log(err)
+	cycleError, ok := err.(*graph.CycleError)
+	if !ok || len(cycleError.Cycles) != 1 || len(recorder.Connections) != 0 {
+		t.Fatalf("refuse cycles problem: %v", err)
+	}
+	if err.Error() != "Equation contains cycles: a -> b -> a" {
+		t.Fatalf("cycle error print problem")
+	}
+
+	err = graph.Evaluate(a.Connect(b), graph.RefuseCycles)
+	if err != nil || len(recorder.Connections) != 1 {
+		t.Fatalf("acyclic evaluation problem: %v", err)
+	}
+}
+
+func TestRefuseManyCycles(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	nodes := G.W(NewConnectable("n00"))
+	for i := 1; i < 12; i++ {
+		nodes = nodes.Add(G.W(NewConnectable(fmt.Sprintf("n%02d", i))))
+	}
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	err := graph.Evaluate(nodes.Connect(nodes).Add(a.Connect(b).Connect(c).Connect(a)), graph.RefuseCycles)
+	t.Log(err)
+	if err == nil || err.Error() != "Equation contains cycles: a -> b -> c -> a; n00 -> n00" {
+		t.Fatalf("one cycle per component expected: %v", err)
+	}
+}
+
+func TestRefuseCompositeCycles(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	err := graph.Evaluate(a.Connect(category.Encapsulate("C", b.Connect(c).Connect(b))), graph.RefuseCycles)
+	t.Log(err)
+	if _, ok := err.(*graph.CycleError); !ok || len(recorder.Connections) != 0 {
+		t.Fatalf("cycles inside the composites should be refused: %v %v", err, recorder.Connections)
+	}
+}
@@ -0,0 +1,58 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"encoding/json"
+	"testing"
+)
+
+func TestDifference(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	before := a.Connect(b)
+	after := a.Connect(c).Add(b)
+
+	if category.CheckEquals(before, a.Connect(b)) != nil || !category.Difference(before, before).IsEmpty() {
+		t.Fatalf("equal categories should have no difference")
+	}
+
+	diff := category.Difference(before, after)
+	t.Log("\n" + diff.String())
+	expected := "a:\n" +
+		"  + a -> c (data)\n" +
+		"  - a -> b (data)\n" +
+		"b:\n" +
+		"  + sink\n" +
+		"c:\n" +
+		"  + source"
+	text := diff.String()
+	if text != expected {
+		t.Fatalf("diff text problem:\n%s", text)
+	}
+
+	serialized, err := json.Marshal(diff)
+	if err != nil {
+		t.Fatalf("json problem: %s", err)
+	}
+	t.Log(string(serialized))
+	parsed := struct {
+		Connectables []category.DiffGroup `json:"connectables"`
+	}{}
+	err = json.Unmarshal(serialized, &parsed)
+	if err != nil || len(parsed.Connectables) != 3 || parsed.Connectables[0].Changes[0].Sink != "c" {
+		t.Fatalf("json content problem: %s", serialized)
+	}
+
+	err = category.CheckEquals(before, after)
+	if _, ok := err.(*category.DiffError); !ok {
+		t.Fatalf("check equals problem: %v", err)
+	}
+}
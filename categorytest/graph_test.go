@@ -0,0 +1,48 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"strings"
+	"testing"
+)
+
+func TestFromCategory(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	control := NewConnectionRecorder("control")
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	g := graph.FromCategory(a.Connect(b.Add(c)).ConnectWith(control, d))
+	if strings.Join(g.Nodes(), ",") != "a,b,c,d" {
+		t.Fatalf("nodes problem: %v", g.Nodes())
+	}
+	if !g.IsSink("a") || g.IsSource("a") || !g.IsSource("d") {
+		t.Fatalf("role problem")
+	}
+	if len(g.Edges()) != 4 || len(g.Edges("control")) != 2 || len(g.Edges("missing")) != 0 {
+		t.Fatalf("edge filter problem: %v", g.Edges())
+	}
+	if g.OutDegree("a") != 2 || g.InDegree("d") != 2 || g.Degree("b") != 2 || g.InDegree("d", "data") != 0 {
+		t.Fatalf("degree problem")
+	}
+	if strings.Join(g.Successors("a"), ",") != "b,c" || strings.Join(g.Predecessors("d"), ",") != "b,c" {
+		t.Fatalf("neighbour problem")
+	}
+	first := g.Out("a")[0]
+	if first.Source != "a" || first.Sink != "b" || first.Operator != "data" || first.Operation == nil {
+		t.Fatalf("edge content problem: %v", first)
+	}
+
+	filtered := g.Filter("data")
+	if len(filtered.Nodes()) != 4 || len(filtered.Edges()) != 2 || filtered.OutDegree("b") != 0 {
+		t.Fatalf("graph filter problem")
+	}
+}
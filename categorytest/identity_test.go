@@ -0,0 +1,90 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+func TestIdentityById(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a1 := NewConnectable("a")
+	a2 := NewConnectable("a")
+	b := NewConnectable("b")
+
+	first := G.W(a1).Connect(G.W(b))
+	second := G.W(a2).Connect(G.W(b))
+	if !first.Equals(second) {
+		t.Fatalf("id policy problem")
+	}
+	if !category.NewFreezedOperation(connect, a1, b).Equals(category.NewFreezedOperation(connect, a2, b)) {
+		t.Fatalf("id policy operation problem")
+	}
+
+	collisions := category.FindIdCollisions(first.Add(second))
+	t.Log(collisions)
+	if len(collisions) != 1 || collisions[0].Id != "a" || len(collisions[0].Connectables) != 2 {
+		t.Fatalf("collision problem")
+	}
+	if len(category.FindIdCollisions(first)) != 0 {
+		t.Fatalf("false collision")
+	}
+}
+
+func TestIdentityByPointer(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect, category.IdentityPolicy(category.IdentityByPointer))
+
+	a1 := NewConnectable("a")
+	a2 := NewConnectable("a")
+	b := NewConnectable("b")
+
+	first := G.W(a1).Connect(G.W(b))
+	second := G.W(a2).Connect(G.W(b))
+	if first.Equals(second) {
+		t.Fatalf("pointer policy problem")
+	}
+	if first.GetOperations().AsArray()[0].Equals(second.GetOperations().AsArray()[0]) {
+		t.Fatalf("pointer policy operation problem")
+	}
+
+	both := first.Add(second)
+	if len(both.GetOperations().AsArray()) != 2 || len(both.GetSinks().AsArray()) != 2 {
+		t.Fatalf("pointer policy set problem")
+	}
+	if !both.Discard(second).GetOperations().Equals(first.GetOperations()) {
+		t.Fatalf("pointer policy discard problem")
+	}
+
+	B := category.NewBuilderFactory(G)
+	_, err := B.W(a1).Add(B.W(a2)).Build()
+	if err != nil {
+		t.Fatalf("same ids are allowed with the pointer policy: %s", err)
+	}
+}
+
+func TestIdentityPolicyPerFactory(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect, category.IdentityPolicy(category.IdentityByPointer))
+	H := category.NewEquationFactory(connect)
+
+	a1 := NewConnectable("a")
+	a2 := NewConnectable("a")
+	b := NewConnectable("b")
+
+	if !H.W(a1).Connect(H.W(b)).Equals(H.W(a2).Connect(H.W(b))) {
+		t.Fatalf("the default factory is affected by the another one")
+	}
+
+	first := category.Reverse(G.W(a1).Connect(G.W(b)))
+	second := category.Reverse(G.W(a2).Connect(G.W(b)))
+	both := first.Add(second)
+	if len(both.GetOperations().AsArray()) != 2 || len(both.GetSources().AsArray()) != 2 {
+		t.Fatalf("reverse lost the pointer policy")
+	}
+}
@@ -0,0 +1,57 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"testing"
+)
+
+func TestIsomorphic(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	control := NewConnectionRecorder("control")
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	w := G.W(NewConnectable("w"))
+	x := G.W(NewConnectable("x"))
+	y := G.W(NewConnectable("y"))
+	z := G.W(NewConnectable("z"))
+
+	production := a.Connect(b.Add(c)).ConnectWith(control, d)
+	staging := z.Connect(y.Add(x)).ConnectWith(control, w)
+
+	mapping, ok := graph.Isomorphic(production, staging)
+	t.Log(mapping)
+	if !ok || mapping["a"] != "z" || mapping["d"] != "w" {
+		t.Fatalf("isomorphism problem: %v", mapping)
+	}
+
+	_, ok = graph.Isomorphic(production, z.Connect(y.Add(x)).Connect(w))
+	if ok {
+		t.Fatalf("operators should be respected")
+	}
+	_, ok = graph.Isomorphic(production, w.ConnectWith(control, y.Add(x)).Connect(z))
+	if ok {
+		t.Fatalf("directions should be respected")
+	}
+
+	chain := a.Connect(b).Connect(c).Connect(d)
+	if _, ok = graph.Isomorphic(chain, w.Connect(x).Connect(y).Connect(z)); !ok {
+		t.Fatalf("chain isomorphism problem")
+	}
+	if _, ok = graph.Isomorphic(chain, w.Connect(x).Connect(y).Add(z)); ok {
+		t.Fatalf("roles should be respected")
+	}
+
+	cycle := a.Connect(b).Connect(c).Connect(a).Add(d.Connect(d))
+	if _, ok = graph.Isomorphic(cycle, z.Connect(z).Add(x.Connect(w).Connect(y).Connect(x))); !ok {
+		t.Fatalf("cycle isomorphism problem")
+	}
+}
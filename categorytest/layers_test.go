@@ -0,0 +1,115 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type lockedRecorder struct {
+	lock        sync.Mutex
+	Connections []string
+}
+
+func (r *lockedRecorder) Evaluate(a category.Connectable, b category.Connectable) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.Connections = append(r.Connections, a.GetId()+" -> "+b.GetId())
+	return nil
+}
+
+func (r *lockedRecorder) GetId() string {
+	return "locked"
+}
+
+func layerIds(layer []category.FreezedOperation) string {
+	ids := make([]string, len(layer))
+	for i, op := range layer {
+		ids[i] = op.GetSource().GetId() + "->" + op.GetSink().GetId()
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestLayers(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	layers, err := graph.Layers(a.Connect(b.Add(c)).Connect(d).Add(a.Connect(d)).Add(b.Connect(c)))
+	if err != nil {
+		t.Fatalf("layers problem: %s", err)
+	}
+	found := []string{}
+	for _, layer := range layers {
+		found = append(found, layerIds(layer))
+	}
+	t.Log(found)
+	if strings.Join(found, " | ") != "a->b,a->c,a->d | b->c,b->d | c->d" {
+		t.Fatalf("layer content problem: %v", found)
+	}
+
+	_, err = graph.Layers(a.Connect(b).Connect(a))
+	if _, ok := err.(*graph.CycleError); !ok {
+		t.Fatalf("cyclic layers problem: %v", err)
+	}
+}
+
+func TestLayeredEvaluation(t *testing.T) {
+	recorder := &lockedRecorder{}
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+	box := category.Encapsulate("box", b.Add(c))
+
+	err := graph.Evaluate(a.Connect(box).Connect(d), graph.Parallel)
+	if err != nil {
+		t.Fatalf("parallel evaluation problem: %s", err)
+	}
+	first := append([]string{}, recorder.Connections[:2]...)
+	sort.Strings(first)
+	if len(recorder.Connections) != 4 || strings.Join(first, ",") != "a -> b,a -> c" {
+		t.Fatalf("layer barrier problem: %v", recorder.Connections)
+	}
+
+	recorder.Connections = nil
+	err = graph.Evaluate(a.Connect(b).Connect(a), graph.Layered)
+	if err == nil || len(recorder.Connections) != 0 {
+		t.Fatalf("layered evaluation should refuse cycles")
+	}
+}
+
+func TestLayeredPlainCategory(t *testing.T) {
+	recorder := &lockedRecorder{}
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	box := category.Encapsulate("box", b.Connect(c))
+
+	err := graph.Evaluate(plainCategory{a.Connect(box)}, graph.Layered)
+	if err != nil {
+		t.Fatalf("plain category evaluation problem: %s", err)
+	}
+	if strings.Join(recorder.Connections, ",") != "a -> b,b -> c" {
+		t.Fatalf("plain category composites should be expanded: %v", recorder.Connections)
+	}
+}
+
+// plainCategory hides the equation term methods
+type plainCategory struct {
+	category.Category
+}
@@ -0,0 +1,71 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"strings"
+	"testing"
+)
+
+func bindingIds(binding map[string]category.Connectable, names ...string) string {
+	ids := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = name + "=" + binding[name].GetId()
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestMatchDiamond(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+	e := G.W(NewConnectable("e"))
+
+	target := a.Connect(b.Add(c)).Connect(d).Connect(e).Add(a.Connect(e))
+	pattern := G.Var("top").Connect(G.Var("left").Add(G.Var("right"))).Connect(G.Var("bottom"))
+
+	found, err := graph.Match(pattern, target)
+	if err != nil {
+		t.Fatalf("match problem: %s", err)
+	}
+	if len(found) != 2 {
+		t.Fatalf("match count problem: %v", found)
+	}
+	if bindingIds(found[0], "top", "left", "right", "bottom") != "top=a,left=b,right=c,bottom=d" ||
+		bindingIds(found[1], "top", "left", "right", "bottom") != "top=a,left=c,right=b,bottom=d" {
+		t.Fatalf("match binding problem: %v", found)
+	}
+}
+
+func TestMatchFixedAndOperators(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	control := NewConnectionRecorder("control")
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	target := a.Connect(b).ConnectWith(control, c).Add(a.Connect(c))
+
+	found, _ := graph.Match(a.Connect(G.Var("x")), target)
+	if len(found) != 2 || found[0]["x"].GetId() != "b" || found[1]["x"].GetId() != "c" {
+		t.Fatalf("fixed node match problem: %v", found)
+	}
+
+	found, _ = graph.Match(G.Var("x").ConnectWith(control, G.Var("y")), target)
+	if len(found) != 1 || bindingIds(found[0], "x", "y") != "x=b,y=c" {
+		t.Fatalf("operator match problem: %v", found)
+	}
+
+	found, _ = graph.Match(G.Var("x").Connect(G.Var("x")), target)
+	if len(found) != 0 {
+		t.Fatalf("self loop match problem: %v", found)
+	}
+}
@@ -0,0 +1,174 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestModuleDefinitions(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	m := category.NewModule("web")
+	frontend, err := m.Define("frontend", a.Add(b).Connect(c))
+	if err != nil {
+		t.Fatalf("define problem: %s", err)
+	}
+
+	first := frontend.Connect(d)
+	t.Log(first.String())
+	if first.String() != "(frontend) * (d)" {
+		t.Fatalf("named print problem")
+	}
+
+	expanded := category.Expand(first)
+	t.Log(expanded.String())
+	if expanded.String() != a.Add(b).Connect(c).Connect(d).String() {
+		t.Fatalf("expand print problem")
+	}
+	if !first.Equals(expanded) || !expanded.Equals(a.Add(b).Connect(c).Connect(d)) {
+		t.Fatalf("expand problem")
+	}
+
+	found, err := m.Get("frontend")
+	if err != nil || !found.Equals(frontend) {
+		t.Fatalf("lookup problem")
+	}
+	_, err = m.Get("backend")
+	if err == nil {
+		t.Fatalf("undefined name should fail")
+	}
+}
+
+func TestModuleImport(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	lib := category.NewModule("lib")
+	_, err := lib.Define("pair", a.Add(b))
+	if err != nil {
+		t.Fatalf("define problem: %s", err)
+	}
+
+	app := category.NewModule("app")
+	err = app.Import(lib)
+	if err != nil {
+		t.Fatalf("import problem: %s", err)
+	}
+
+	pair, err := app.Get("lib.pair")
+	if err != nil {
+		t.Fatalf("imported lookup problem: %s", err)
+	}
+	first := pair.Connect(c)
+	t.Log(first.String())
+	if first.String() != "(lib.pair) * (c)" {
+		t.Fatalf("imported print problem")
+	}
+	if !first.Equals(a.Add(b).Connect(c)) {
+		t.Fatalf("imported definition problem")
+	}
+
+	err = lib.Import(app)
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("import cycle should fail")
+	}
+}
+
+func TestModuleDefinitionCycle(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+
+	m := category.NewModule("m")
+	x, err := m.Define("x", a)
+	if err != nil {
+		t.Fatalf("define problem: %s", err)
+	}
+	y, err := m.Define("y", x.Connect(b))
+	if err != nil {
+		t.Fatalf("define problem: %s", err)
+	}
+
+	_, err = m.Define("x", y.Add(a))
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("definition cycle should fail")
+	}
+	if !x.Equals(a) {
+		t.Fatalf("failed definition should not change the binding")
+	}
+
+	_, err = m.Define("x", a.Add(b))
+	if err != nil {
+		t.Fatalf("redefine problem: %s", err)
+	}
+	if !y.Equals(a.Add(b).Connect(b)) {
+		t.Fatalf("redefinition should be seen through the name")
+	}
+}
+
+func TestDeepDefinitions(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	m := category.NewModule("deep")
+
+	previous, err := m.Define("d0", G.W(NewConnectable("x0")))
+	if err != nil {
+		t.Fatalf("define problem: %s", err)
+	}
+	for i := 1; i <= 30; i++ {
+		previous, err = m.Define(fmt.Sprintf("d%d", i), previous.Connect(G.W(NewConnectable(fmt.Sprintf("x%d", i)))))
+		if err != nil {
+			t.Fatalf("define problem: %s", err)
+		}
+	}
+	if len(previous.GetOperations().AsArray()) != 30 {
+		t.Fatalf("deep definition problem")
+	}
+
+	_, err = m.Define("d0", G.W(NewConnectable("y0")))
+	if err != nil {
+		t.Fatalf("redefine problem: %s", err)
+	}
+	if !previous.GetSinks().Contains(NewConnectable("y0")) {
+		t.Fatalf("cached definition should be refreshed after redefinition")
+	}
+}
+
+func TestConcurrentNamedTerms(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	m := category.NewModule("m")
+
+	inner, _ := m.Define("inner", G.W(NewConnectable("a")).Connect(G.W(NewConnectable("b"))))
+	outer, _ := m.Define("outer", inner.Connect(G.W(NewConnectable("c"))))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if len(outer.GetOperations().AsArray()) != 2 || len(outer.GetSources().AsArray()) != 1 {
+				t.Errorf("concurrent lookup problem")
+			}
+		}()
+	}
+	wg.Wait()
+}
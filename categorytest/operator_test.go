@@ -0,0 +1,50 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"strings"
+	"testing"
+)
+
+func TestConnectWith(t *testing.T) {
+	data := NewConnectionRecorder("data")
+	control := NewConnectionRecorder("control")
+	G := category.NewEquationFactory(data)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	first := a.Connect(b).ConnectWith(control, c)
+	t.Log(first.String())
+	if first.String() != "((a) * (b)) *[control] (c)" {
+		t.Fatalf("operator print problem")
+	}
+	if first.GetOperator().GetId() != "data" {
+		t.Fatalf("default operator problem")
+	}
+
+	err := first.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	if strings.Join(data.Connections, ",") != "a -> b" || strings.Join(control.Connections, ",") != "b -> c" {
+		t.Fatalf("evaluation operator problem: %v %v", data.Connections, control.Connections)
+	}
+
+	if first.Equals(a.Connect(b).Connect(c)) {
+		t.Fatalf("operations with different operators should differ")
+	}
+	if !first.Equals(a.Connect(b.ConnectWith(control, c))) {
+		t.Fatalf("multi operator equality problem")
+	}
+
+	reversed := category.Reverse(first)
+	if !reversed.Equals(c.ConnectWith(control, b).Connect(a)) {
+		t.Fatalf("multi operator reverse problem")
+	}
+}
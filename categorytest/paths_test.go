@@ -0,0 +1,71 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"strings"
+	"testing"
+)
+
+func pathIds(path []category.Connectable) string {
+	ids := make([]string, len(path))
+	for i, connectable := range path {
+		ids[i] = connectable.GetId()
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestPaths(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	control := NewConnectionRecorder("control")
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+	e := G.W(NewConnectable("e"))
+
+	diamond := a.Connect(b.Add(c)).Connect(d).ConnectWith(control, e).Add(a.Connect(d))
+
+	if !graph.Reachable(diamond, "a", "e") || graph.Reachable(diamond, "e", "a") {
+		t.Fatalf("reachable problem")
+	}
+	if graph.Reachable(diamond, "a", "e", "data") || !graph.Reachable(diamond, "a", "d", "data") {
+		t.Fatalf("reachable operator filter problem")
+	}
+
+	path, found := graph.ShortestPath(diamond, "a", "e")
+	if !found || pathIds(path) != "a,d,e" {
+		t.Fatalf("shortest path problem: %v", path)
+	}
+	_, found = graph.ShortestPath(diamond, "b", "c")
+	if found {
+		t.Fatalf("no path problem")
+	}
+
+	paths := graph.AllSimplePaths(diamond, "a", "e", 0)
+	names := []string{}
+	for _, p := range paths {
+		names = append(names, pathIds(p))
+	}
+	if strings.Join(names, " ") != "a,b,d,e a,c,d,e a,d,e" {
+		t.Fatalf("all simple paths problem: %v", names)
+	}
+	if len(graph.AllSimplePaths(diamond, "a", "e", 2)) != 2 {
+		t.Fatalf("path limit problem")
+	}
+
+	if pathIds(graph.Upstream(diamond, "d").AsSortedArray()) != "a,b,c" {
+		t.Fatalf("upstream problem")
+	}
+	if pathIds(graph.Downstream(diamond, "b").AsSortedArray()) != "d,e" {
+		t.Fatalf("downstream problem")
+	}
+	if pathIds(graph.Downstream(diamond, "b", "data").AsSortedArray()) != "d" {
+		t.Fatalf("downstream operator filter problem")
+	}
+}
@@ -0,0 +1,184 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"strings"
+	"testing"
+)
+
+type component struct {
+	Id      string
+	Inputs  []string
+	Outputs []string
+}
+
+func (c *component) GetId() string {
+	return c.Id
+}
+
+func (c *component) GetInputs() []string {
+	return c.Inputs
+}
+
+func (c *component) GetOutputs() []string {
+	return c.Outputs
+}
+
+type portRecorder struct {
+	Connections []string
+}
+
+func (r *portRecorder) Evaluate(a category.Connectable, b category.Connectable) error {
+	source := a.(category.Port)
+	sink := b.(category.Port)
+	r.Connections = append(r.Connections,
+		source.GetOwner().GetId()+":"+source.GetName()+" -> "+sink.GetOwner().GetId()+":"+sink.GetName())
+	return nil
+}
+
+func (r *portRecorder) GetId() string {
+	return "ports"
+}
+
+func TestPortedConnectables(t *testing.T) {
+	recorder := &portRecorder{}
+	G := category.NewEquationFactory(recorder)
+
+	adder := &component{Id: "adder", Inputs: []string{"a", "b"}, Outputs: []string{"out"}}
+	printer := &component{Id: "printer", Inputs: []string{"in"}}
+
+	first := G.P(adder).Connect(G.P(printer))
+	sinks := first.GetSinks().AsSortedArray()
+	if len(first.GetSources().AsArray()) != 0 || len(sinks) != 2 || sinks[0].GetId() != "adder.a" || sinks[1].GetId() != "adder.b" {
+		t.Fatalf("ported term sets problem: %s %s", first.GetSources(), first.GetSinks())
+	}
+	second := G.Port(adder, "out").Connect(G.Port(printer, "in"))
+	if !first.Discard(G.P(adder)).Equals(second) {
+		t.Fatalf("port term problem")
+	}
+
+	err := first.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	if strings.Join(recorder.Connections, ",") != "adder:out -> printer:in" {
+		t.Fatalf("port evaluation problem: %v", recorder.Connections)
+	}
+
+	if category.ValidatePorts(first) != nil {
+		t.Fatalf("valid ports problem")
+	}
+	err = category.ValidatePorts(category.Reverse(first))
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("outputs used as sinks should be found")
+	}
+}
+
+func TestPortBuilder(t *testing.T) {
+	B := category.NewBuilderFactory(category.NewEquationFactory(&portRecorder{}))
+
+	adder := &component{Id: "adder", Inputs: []string{"a"}, Outputs: []string{"out"}}
+	printer := &component{Id: "printer", Inputs: []string{"in"}}
+
+	_, err := B.Port(adder, "out").Connect(B.Port(printer, "in")).Build()
+	if err != nil {
+		t.Fatalf("port build problem: %s", err)
+	}
+
+	_, err = B.Port(adder, "missing").Connect(B.P(printer)).Build()
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("unknown port should be an error")
+	}
+
+	_, err = B.P(adder).Connect(B.W(category.NewOutputPort(adder, "out"))).Build()
+	t.Log(err)
+	if err == nil || !strings.Contains(err.Error(), "Output port 'adder.out' is used as a sink") {
+		t.Fatalf("output port sink should be an error")
+	}
+}
+
+func TestUnknownPort(t *testing.T) {
+	G := category.NewEquationFactory(&portRecorder{})
+	adder := &component{Id: "adder", Inputs: []string{"a"}, Outputs: []string{"out"}}
+
+	_, err := category.TryPort(G, adder, "missing")
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("unknown port should be an error")
+	}
+	term, err := category.TryPort(G, adder, "out")
+	if err != nil || term.GetSources().AsArray()[0].GetId() != "adder.out" {
+		t.Fatalf("try port problem: %s", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("unknown port should panic")
+		}
+	}()
+	G.Port(adder, "missing")
+}
+
+func TestEvaluateValidatesPorts(t *testing.T) {
+	recorder := &portRecorder{}
+	G := category.NewEquationFactory(recorder)
+
+	adder := &component{Id: "adder", Inputs: []string{"a"}, Outputs: []string{"out"}}
+	printer := &component{Id: "printer", Inputs: []string{"in"}}
+
+	reversed := category.Reverse(G.P(adder).Connect(G.P(printer)))
+	for _, evaluate := range []func() error{reversed.Evaluate, reversed.EvaluateSorted} {
+		err := evaluate()
+		t.Log(err)
+		if err == nil {
+			t.Fatalf("invalid ports should not be evaluated")
+		}
+	}
+	err := graph.Evaluate(reversed, graph.Layered)
+	if err == nil {
+		t.Fatalf("invalid ports should not be evaluated in layers")
+	}
+	if len(recorder.Connections) != 0 {
+		t.Fatalf("nothing should be connected: %v", recorder.Connections)
+	}
+}
+
+func TestValuePortedConnectable(t *testing.T) {
+	G := category.NewEquationFactory(&portRecorder{})
+	B := category.NewBuilderFactory(G)
+
+	a := valueComponent{Id: "a", Inputs: []string{"in"}, Outputs: []string{"out"}}
+	b := valueComponent{Id: "b", Inputs: []string{"in"}, Outputs: []string{"out"}}
+
+	_, err := B.P(a).Connect(B.P(b)).Connect(B.P(a)).Build()
+	if err != nil {
+		t.Fatalf("value ported build problem: %s", err)
+	}
+	collisions := category.FindIdCollisions(G.P(a).Connect(G.P(b)).Add(G.P(b).Connect(G.P(a))))
+	if len(collisions) != 0 {
+		t.Fatalf("false collision: %v", collisions)
+	}
+
+	P := category.NewEquationFactory(&portRecorder{}, category.IdentityPolicy(category.IdentityByPointer))
+	if !P.P(a).Equals(P.P(a)) {
+		t.Fatalf("value ported pointer policy problem")
+	}
+}
+
+// valueComponent is a ported connectable used by value. Its ports are not comparable with ==
+type valueComponent struct {
+	Id      string
+	Inputs  []string
+	Outputs []string
+}
+
+func (c valueComponent) GetId() string        { return c.Id }
+func (c valueComponent) GetInputs() []string  { return c.Inputs }
+func (c valueComponent) GetOutputs() []string { return c.Outputs }
@@ -0,0 +1,89 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+func TestProveIdentity(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+	e := G.W(NewConnectable("e"))
+	I := G.I()
+
+	first := a.Add(b).Connect(c.Add(d)).Connect(e).Add(a.Add(b).Connect(e))
+	simplified := a.Add(b).Connect(c.Add(d).Add(I)).Connect(e)
+
+	proof, err := category.Prove(first, simplified)
+	if err != nil {
+		t.Fatalf("prove problem: %s", err)
+	}
+	t.Log(proof.String())
+
+	if proof.Counterexample != nil || len(proof.Steps) == 0 {
+		t.Fatalf("proof problem")
+	}
+	if proof.Steps[len(proof.Steps)-1].Term != simplified.String() {
+		t.Fatalf("proof should end with the second term")
+	}
+
+	laws := make(map[string]bool)
+	for _, step := range proof.Steps {
+		laws[step.Law] = true
+	}
+	for _, law := range []string{category.LawRightDistributivity, category.LawLeftDistributivity, category.LawLeftIdentity} {
+		if !laws[law] {
+			t.Fatalf("expected the law '%s' in the proof", law)
+		}
+	}
+}
+
+func TestProveCommutativity(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	O := G.O()
+
+	proof, err := category.Prove(a.Add(b).Add(O), b.Add(a))
+	if err != nil {
+		t.Fatalf("prove problem: %s", err)
+	}
+	t.Log(proof.String())
+	if proof.Counterexample != nil || len(proof.Steps) != 2 {
+		t.Fatalf("proof problem")
+	}
+}
+
+func TestProveCounterexample(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	proof, err := category.Prove(a.Connect(b).Connect(c), a.Connect(c).Add(b))
+	if err != nil {
+		t.Fatalf("prove problem: %s", err)
+	}
+	t.Log(proof.String())
+
+	counterexample := proof.Counterexample
+	if counterexample == nil || counterexample.Operation == nil || !counterexample.InFirst {
+		t.Fatalf("counterexample problem")
+	}
+	if counterexample.Operation.GetSource().GetId() != "a" || counterexample.Operation.GetSink().GetId() != "b" {
+		t.Fatalf("counterexample operation problem")
+	}
+}
@@ -0,0 +1,97 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestOperatorRegistry(t *testing.T) {
+	data := NewConnectionRecorder("data")
+	control := NewConnectionRecorder("control")
+
+	operators := category.NewOperatorRegistry()
+	if operators.Register(data) != nil || operators.Register(control) != nil || operators.Register(data) != nil {
+		t.Fatalf("register problem")
+	}
+	if operators.Register(NewConnectionRecorder("data")) == nil {
+		t.Fatalf("registering another operator with the same id should fail")
+	}
+	if operators.Alias("ctl", "control") != nil {
+		t.Fatalf("alias problem")
+	}
+	if operators.Alias("x", "missing") == nil || operators.Alias("data", "control") == nil {
+		t.Fatalf("invalid alias should fail")
+	}
+
+	found, ok := operators.Lookup("ctl")
+	if !ok || found != control {
+		t.Fatalf("alias lookup problem")
+	}
+	list := operators.List()
+	if len(list) != 2 || list[0] != control || list[1] != data {
+		t.Fatalf("list problem")
+	}
+}
+
+func TestConnectableRegistryConcurrency(t *testing.T) {
+	connectables := category.NewConnectableRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := NewConnectable(fmt.Sprintf("c%02d", i))
+			err := connectables.Register(c)
+			if err != nil {
+				t.Errorf("register problem: %s", err)
+			}
+			found, ok := connectables.Lookup(c.GetId())
+			if !ok || found != c {
+				t.Errorf("lookup problem")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(connectables.List()) != 20 || connectables.List()[0].GetId() != "c00" {
+		t.Fatalf("list problem")
+	}
+}
+
+func TestParseRegistered(t *testing.T) {
+	data := NewConnectionRecorder("data")
+	control := NewConnectionRecorder("control")
+	G := category.NewEquationFactory(data)
+
+	operators := category.NewOperatorRegistry()
+	_ = operators.Register(control)
+	connectables := category.NewConnectableRegistry()
+	a := NewConnectable("a")
+	b := NewConnectable("b")
+	c := NewConnectable("c")
+	for _, connectable := range []category.Connectable{a, b, c} {
+		_ = connectables.Register(connectable)
+	}
+
+	expected := G.W(a).Connect(G.W(b)).ConnectWith(control, G.W(c))
+	parsed, err := category.ParseRegistered(G, expected.String(), operators, connectables)
+	if err != nil {
+		t.Fatalf("parse problem: %s", err)
+	}
+	if !parsed.Equals(expected) {
+		t.Fatalf("registered parse problem")
+	}
+
+	_, err = category.ParseRegistered(G, "a *[missing] b", operators, connectables)
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("unknown operator should fail")
+	}
+}
@@ -0,0 +1,64 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	first := a.Add(b).Connect(c)
+	reversed := category.Reverse(first)
+	expected := c.Connect(a.Add(b))
+
+	t.Log(first.String())
+	t.Log(reversed.String())
+
+	if !reversed.Equals(expected) {
+		t.Fatalf("reverse problem")
+	}
+	if reversed.String() != expected.String() {
+		t.Fatalf("reverse print problem")
+	}
+	if !reversed.GetSources().Equals(first.GetSinks()) || !reversed.GetSinks().Equals(first.GetSources()) {
+		t.Fatalf("reverse sources and sinks problem")
+	}
+	if !category.Reverse(reversed).Equals(first) {
+		t.Fatalf("double reverse problem")
+	}
+}
+
+func TestReverseIdentityAndZero(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	I := G.I()
+	O := G.O()
+
+	first := O.Connect(a.Connect(b.Add(I)).Connect(c))
+	expected := c.Connect(b.Add(I)).Connect(a).Connect(O)
+
+	reversed := category.Reverse(first)
+	t.Log(reversed.String())
+
+	if !reversed.Equals(expected) {
+		t.Fatalf("reverse problem")
+	}
+	err := reversed.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+}
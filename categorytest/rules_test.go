@@ -0,0 +1,95 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	control := NewConnectionRecorder("control")
+
+	testA := G.W(NewConnectable("test/a"))
+	testB := G.W(NewConnectable("test/b"))
+	prodA := G.W(NewConnectable("prod/a"))
+	prodB := G.W(NewConnectable("prod/b"))
+
+	wiring := testA.Add(testB).Add(prodA).Connect(prodB).Add(prodB.ConnectWith(control, prodA))
+
+	if len(graph.Validate(wiring, graph.MaxFanIn(3), graph.RequiredEdge("prod/a", "prod/b"))) != 0 {
+		t.Fatalf("valid wiring problem")
+	}
+
+	violations := graph.Validate(wiring,
+		graph.MaxFanIn(2),
+		graph.MaxFanOut(1, "control"),
+		graph.ForbiddenEdges("test/*", "prod/*"),
+		graph.RequiredEdge("prod/b", "test/*"),
+		graph.Acyclic(),
+		graph.AllowedOperators("data"))
+	for _, v := range violations {
+		t.Log(v)
+	}
+
+	expected := []struct {
+		rule       string
+		operations int
+	}{
+		{"max fan-in 2", 3},
+		{"forbidden test/* -> prod/*", 1},
+		{"forbidden test/* -> prod/*", 1},
+		{"required prod/b -> test/*", 0},
+		{"acyclic", 2},
+		{"allowed operators data", 1},
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("violation count problem: %v", violations)
+	}
+	for i, e := range expected {
+		if violations[i].Rule != e.rule || len(violations[i].Operations) != e.operations {
+			t.Fatalf("violation problem: %s", violations[i])
+		}
+	}
+	if violations[1].String() != "forbidden test/* -> prod/*: Edge test/a -> prod/b is forbidden [test/a -> prod/b (data)]" {
+		t.Fatalf("violation print problem: %s", violations[1])
+	}
+
+	custom := graph.NewRule("no self loops", func(g *graph.Graph) []graph.Violation {
+		violations := []graph.Violation{}
+		for _, e := range g.Edges() {
+			if e.Source == e.Sink {
+				violations = append(violations, graph.Violation{Rule: "no self loops", Message: e.Source})
+			}
+		}
+		return violations
+	})
+	if len(graph.Validate(wiring.Add(testA.Connect(testA)), custom)) != 1 {
+		t.Fatalf("custom rule problem")
+	}
+}
+
+func TestAcyclicComponents(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	triangle := a.Connect(b).Connect(c).Connect(a).Add(a.Connect(c)).Add(c.Connect(d)).Add(d.Connect(d))
+	violations := graph.Validate(triangle, graph.Acyclic())
+	for _, v := range violations {
+		t.Log(v)
+	}
+	if len(violations) != 2 || violations[0].Message != "Cycles between a, b, c" || len(violations[0].Operations) != 4 {
+		t.Fatalf("one violation per component expected: %v", violations)
+	}
+	if violations[1].Message != "Cycles between d" || len(violations[1].Operations) != 1 {
+		t.Fatalf("self loop violation problem: %s", violations[1])
+	}
+}
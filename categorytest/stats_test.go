@@ -0,0 +1,53 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"category/graph"
+	"encoding/json"
+	"testing"
+)
+
+func TestStatistics(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	control := NewConnectionRecorder("control")
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+	e := G.W(NewConnectable("e"))
+
+	stats := graph.Statistics(a.Connect(b.Add(c)).Connect(d).ConnectWith(control, e))
+	t.Log("\n" + stats.String())
+	if stats.Nodes != 5 || stats.Edges != 5 || stats.EdgesPerOperator["data"] != 4 || stats.NodesPerOperator["control"] != 2 {
+		t.Fatalf("count problem: %+v", stats)
+	}
+	if stats.FanOut[2] != 1 || stats.FanIn[2] != 1 || stats.MaxFanIn != 2 || stats.MaxFanOut != 2 {
+		t.Fatalf("fan problem: %+v", stats)
+	}
+	if stats.Roots != 1 || stats.Leaves != 1 || stats.LongestPath != 3 || !stats.Acyclic {
+		t.Fatalf("path problem: %+v", stats)
+	}
+	if stats.Density != 0.25 {
+		t.Fatalf("density problem: %v", stats.Density)
+	}
+
+	serialized, err := stats.JSON()
+	if err != nil {
+		t.Fatalf("json problem: %s", err)
+	}
+	parsed := graph.Stats{}
+	err = json.Unmarshal(serialized, &parsed)
+	if err != nil || parsed.Edges != 5 || parsed.FanIn[0] != 1 {
+		t.Fatalf("json content problem: %s", serialized)
+	}
+
+	cyclic := graph.Statistics(a.Connect(b).Connect(a))
+	if cyclic.Acyclic || cyclic.LongestPath != -1 || cyclic.Roots != 0 {
+		t.Fatalf("cyclic problem: %+v", cyclic)
+	}
+}
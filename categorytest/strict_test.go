@@ -0,0 +1,78 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+func TestTryOperations(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+	H := category.NewEquationFactory(NewConnectionRecorder("control"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := H.W(NewConnectable("c"))
+
+	first, err := category.TryConnect(a, b)
+	if err != nil || !first.Equals(a.Connect(b)) {
+		t.Fatalf("try connect problem: %s", err)
+	}
+
+	_, err = category.TryAdd(first, c)
+	t.Log(err)
+	mismatch, ok := err.(*category.OperatorMismatchError)
+	if !ok {
+		t.Fatalf("expected *OperatorMismatchError, got %v", err)
+	}
+	if mismatch.Expected != "data" || mismatch.Got != "control" || mismatch.Term != c {
+		t.Fatalf("mismatch content problem")
+	}
+}
+
+func TestStrictFactory(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"), category.MustMatchOperators)
+	H := category.NewEquationFactory(NewConnectionRecorder("control"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := H.W(NewConnectable("c"))
+
+	_ = a.Add(b).Connect(G.I())
+
+	defer func() {
+		r := recover()
+		t.Log(r)
+		_, ok := r.(*category.OperatorMismatchError)
+		if !ok {
+			t.Fatalf("expected panic with *OperatorMismatchError, got %v", r)
+		}
+	}()
+	_ = a.Add(b).Connect(c)
+	t.Fatalf("strict mode should panic")
+}
+
+func TestMustMatchRightOperand(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"), category.MustMatchOperators)
+	H := category.NewEquationFactory(NewConnectionRecorder("control"))
+
+	a := G.W(NewConnectable("a"))
+	c := H.W(NewConnectable("c"))
+
+	panics := func(operation func()) (found bool) {
+		defer func() {
+			_, found = recover().(*category.OperatorMismatchError)
+		}()
+		operation()
+		return false
+	}
+	if !panics(func() { c.Add(a) }) || !panics(func() { c.Connect(a.Add(a)) }) {
+		t.Fatalf("strict right operand should panic")
+	}
+	if panics(func() { c.Add(H.W(NewConnectable("d"))) }) {
+		t.Fatalf("non strict operands should not panic")
+	}
+}
@@ -0,0 +1,76 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type wiring struct {
+	Id    string
+	Wired map[string]bool
+	Log   []string
+	Fail  string
+}
+
+func (w *wiring) Evaluate(a category.Connectable, b category.Connectable) error {
+	key := a.GetId() + " -> " + b.GetId()
+	w.Wired[key] = true
+	w.Log = append(w.Log, "+"+key)
+	return nil
+}
+
+func (w *wiring) Disconnect(a category.Connectable, b category.Connectable) error {
+	key := a.GetId() + " -> " + b.GetId()
+	if key == w.Fail {
+		return fmt.Errorf("can not disconnect %s", key)
+	}
+	delete(w.Wired, key)
+	w.Log = append(w.Log, "-"+key)
+	return nil
+}
+
+func (w *wiring) GetId() string {
+	return w.Id
+}
+
+func TestTeardown(t *testing.T) {
+	data := &wiring{Id: "data", Wired: make(map[string]bool)}
+	G := category.NewEquationFactory(data)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	first := a.Connect(b).Connect(c).ConnectWith(NewConnectionRecorder("control"), d)
+
+	err := first.EvaluateSorted()
+	if err != nil {
+		t.Fatalf("evaluate problem: %s", err)
+	}
+	err = first.Teardown()
+	if err != nil {
+		t.Fatalf("teardown problem: %s", err)
+	}
+	t.Log(data.Log)
+	if len(data.Wired) != 0 {
+		t.Fatalf("connections left: %v", data.Wired)
+	}
+	if strings.Join(data.Log, ",") != "+a -> b,+b -> c,-b -> c,-a -> b" {
+		t.Fatalf("teardown order problem: %v", data.Log)
+	}
+
+	data.Fail = "b -> c"
+	_ = first.EvaluateSorted()
+	err = first.Teardown()
+	t.Log(err)
+	if err == nil || !data.Wired["a -> b"] {
+		t.Fatalf("teardown should stop at the first error")
+	}
+}
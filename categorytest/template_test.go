@@ -0,0 +1,147 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	names := map[string]category.Category{"a": a, "b": b, "c": c}
+
+	first, err := category.Parse(G, "O * (a + b) * (c + I) - a * c * O", names)
+	if err != nil {
+		t.Fatalf("parse problem: %s", err)
+	}
+	second := G.O().Connect(a.Add(b)).Connect(c.Add(G.I())).Discard(a.Connect(c).Connect(G.O()))
+	t.Log(first.String())
+	if !first.Equals(second) || first.String() != second.String() {
+		t.Fatalf("parse problem")
+	}
+
+	printed, err := category.Parse(G, second.String(), names)
+	if err != nil || !printed.Equals(second) {
+		t.Fatalf("printed form parse problem")
+	}
+
+	for _, text := range []string{"a +", "(a * b", "a * d", "a b"} {
+		_, err = category.Parse(G, text, names)
+		t.Log(err)
+		if err == nil {
+			t.Fatalf("parsing '%s' should fail", text)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	w1 := G.W(NewConnectable("w1"))
+	w2 := G.W(NewConnectable("w2"))
+	w3 := G.W(NewConnectable("w3"))
+	collector := G.W(NewConnectable("collector"))
+
+	body := func(G category.EquationFactory, args map[string]category.EquationTerm) category.EquationTerm {
+		return args["workers"].Connect(args["collector"])
+	}
+	fanin, err := category.NewTemplate(G, "fanin", []string{"workers", "collector"}, body)
+	if err != nil {
+		t.Fatalf("template problem: %s", err)
+	}
+	_, err = category.NewTemplate(G, "fanin", []string{"workers", "workers"}, body)
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("duplicate parameters should be refused")
+	}
+	parsed, err := category.ParseTemplate(G, "fanin", []string{"workers", "collector"}, "workers * collector")
+	if err != nil {
+		t.Fatalf("template parse problem: %s", err)
+	}
+	t.Log(fanin.String())
+
+	bindings := map[string]category.Category{
+		"workers":   w1.Add(w2).Add(w3),
+		"collector": collector}
+
+	first, err := fanin.Instantiate(bindings)
+	if err != nil {
+		t.Fatalf("instantiate problem: %s", err)
+	}
+	second, err := parsed.Instantiate(bindings)
+	if err != nil {
+		t.Fatalf("instantiate problem: %s", err)
+	}
+	if !first.Equals(second) || !first.Equals(w1.Add(w2).Add(w3).Connect(collector)) {
+		t.Fatalf("template problem")
+	}
+	if len(first.GetOperations().AsArray()) != 3 {
+		t.Fatalf("fan-in problem")
+	}
+
+	_, err = fanin.Instantiate(map[string]category.Category{"workers": w1})
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("missing binding should fail")
+	}
+
+	_, err = fanin.Instantiate(map[string]category.Category{"workers": w1, "collector": collector, "extra": w2})
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("extra binding should fail")
+	}
+
+	_, err = category.ParseTemplate(G, "broken", []string{"workers"}, "workers * collector")
+	t.Log(err)
+	if err == nil {
+		t.Fatalf("unknown parameter should fail")
+	}
+}
+
+func TestTemplateCall(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	fanin, err := category.ParseTemplate(G, "fanin", []string{"workers", "collector"}, "workers * collector")
+	if err != nil {
+		t.Fatalf("template parse problem: %s", err)
+	}
+	templates := map[string]category.Template{"fanin": fanin}
+	names := map[string]category.Category{"a": a, "b": b, "c": c}
+
+	first, err := category.ParseWithTemplates(G, "fanin(a + b, c) + I", names, templates)
+	if err != nil {
+		t.Fatalf("template call problem: %s", err)
+	}
+	if !first.Equals(a.Add(b).Connect(c).Add(G.I())) {
+		t.Fatalf("template call result problem: %s", first)
+	}
+
+	for _, text := range []string{"fanin(a)", "fanin(a, b, c)", "fanout(a, b)", "fanin(a b)"} {
+		_, err = category.ParseWithTemplates(G, text, names, templates)
+		t.Log(err)
+		if err == nil {
+			t.Fatalf("%s should fail", text)
+		}
+	}
+
+	for _, parameters := range [][]string{{"I"}, {"O", "x"}, {"x", "x"}, {"$x"}, {"a b"}} {
+		_, err = category.ParseTemplate(G, "broken", parameters, "x")
+		t.Log(err)
+		if err == nil {
+			t.Fatalf("parameters %v should be rejected", parameters)
+		}
+	}
+}
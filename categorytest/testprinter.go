@@ -52,3 +52,28 @@ func (c *ConnectionPrinter) GetId() string {
 func NewConnectionPrinter() category.Operator {
 	return &ConnectionPrinter{}
 }
+
+// ConnectionRecorder implements the category.Operator -interface and records the connections for testing purposes
+type ConnectionRecorder struct {
+	Id          string
+	Connections []string
+}
+
+// Evaluate records a.GetId() -> b.GetId()
+func (c *ConnectionRecorder) Evaluate(a category.Connectable, b category.Connectable) error {
+	if a == nil || b == nil {
+		return fmt.Errorf("connecting nil")
+	}
+	c.Connections = append(c.Connections, a.GetId()+" -> "+b.GetId())
+	return nil
+}
+
+// GetId returns the identifier given for this recorder
+func (c *ConnectionRecorder) GetId() string {
+	return c.Id
+}
+
+// NewConnectionRecorder returns a new category.Operator instance recording the connections for testing purposes
+func NewConnectionRecorder(id string) *ConnectionRecorder {
+	return &ConnectionRecorder{Id: id}
+}
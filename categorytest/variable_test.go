@@ -0,0 +1,124 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+package categorytest
+
+import (
+	"category"
+	"strings"
+	"testing"
+)
+
+func TestVariableBind(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+	d := G.W(NewConnectable("d"))
+
+	partial := a.Connect(G.Var("service")).Connect(d).Add(G.Var("extra"))
+	t.Log(partial.String())
+	if partial.String() != "(((a) * ($service)) * (d)) + ($extra)" {
+		t.Fatalf("variable print problem")
+	}
+
+	free := category.FreeVars(partial)
+	if strings.Join(free, ",") != "extra,service" {
+		t.Fatalf("free variables problem")
+	}
+
+	bound, err := category.Bind(partial, map[string]category.Category{"service": b.Add(c)})
+	if err != nil {
+		t.Fatalf("bind problem: %s", err)
+	}
+	if strings.Join(category.FreeVars(bound), ",") != "extra" {
+		t.Fatalf("partial bind problem")
+	}
+
+	bound, err = category.Bind(bound, map[string]category.Category{"extra": G.O()})
+	if err != nil {
+		t.Fatalf("bind problem: %s", err)
+	}
+	t.Log(bound.String())
+	if len(category.FreeVars(bound)) != 0 || !bound.Equals(a.Connect(b.Add(c)).Connect(d)) {
+		t.Fatalf("bind problem")
+	}
+}
+
+func TestVariableParseAndReverse(t *testing.T) {
+	connect := NewConnectionPrinter()
+	G := category.NewEquationFactory(connect)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	partial, err := category.Parse(G, "a * $x", map[string]category.Category{"a": a})
+	if err != nil {
+		t.Fatalf("parse problem: %s", err)
+	}
+
+	reversed := category.Reverse(partial)
+	t.Log(reversed.String())
+
+	bound, err := category.Bind(reversed, map[string]category.Category{"x": b.Connect(c)})
+	if err != nil {
+		t.Fatalf("bind problem: %s", err)
+	}
+	if !bound.Equals(c.Connect(b).Connect(a)) {
+		t.Fatalf("reversed variable problem")
+	}
+}
+
+func TestEvaluateFreeVariables(t *testing.T) {
+	recorder := NewConnectionRecorder("data")
+	G := category.NewEquationFactory(recorder)
+
+	a := G.W(NewConnectable("a"))
+	b := G.W(NewConnectable("b"))
+	c := G.W(NewConnectable("c"))
+
+	partial := a.Connect(G.Var("x")).Connect(b).Add(b.Connect(c)).Add(G.Var("y"))
+	for _, err := range []error{partial.Evaluate(), partial.EvaluateSorted(), partial.Teardown(), G.Var("z").Evaluate()} {
+		t.Log(err)
+		if err == nil {
+			t.Fatalf("free variables should be an error")
+		}
+	}
+	if partial.EvaluateSorted().Error() != "Unbound variables: x, y" {
+		t.Fatalf("free variable error problem")
+	}
+	if len(recorder.Connections) != 0 {
+		t.Fatalf("nothing should be connected: %v", recorder.Connections)
+	}
+
+	bound, _ := category.Bind(partial, map[string]category.Category{"x": c, "y": G.I()})
+	if bound.EvaluateSorted() != nil || len(recorder.Connections) != 3 {
+		t.Fatalf("bound evaluation problem: %v", recorder.Connections)
+	}
+}
+
+func TestSharedSubterms(t *testing.T) {
+	G := category.NewEquationFactory(NewConnectionRecorder("data"))
+
+	bound := G.W(NewConnectable("a"))
+	free := G.Var("x").Add(G.W(NewConnectable("b")))
+	for i := 0; i < 60; i++ {
+		bound = bound.Add(bound)
+		free = free.Add(free)
+	}
+
+	if bound.EvaluateSorted() != nil || bound.Teardown() != nil {
+		t.Fatalf("bound shared term evaluation problem")
+	}
+	err := free.EvaluateSorted()
+	if err == nil || err.Error() != "Unbound variables: x" {
+		t.Fatalf("free shared term evaluation problem: %v", err)
+	}
+	if len(category.FreeVars(free)) != 1 {
+		t.Fatalf("free vars problem")
+	}
+}
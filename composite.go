@@ -0,0 +1,185 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+// Composite is a connectable encapsulating a whole sub-equation. It is a single node on the equations,
+// but when evaluated the connections to it are made to the sinks of the encapsulated term,
+// the connections from it are made from the sources of the encapsulated term and the
+// operations of the encapsulated term are evaluated too
+type Composite interface {
+	Connectable
+	// GetTerm returns the encapsulated term
+	GetTerm() EquationTerm
+}
+
+// Encapsulate returns a term wrapping the term as a composite connectable with the given name as its id
+func Encapsulate(name string, term EquationTerm) EquationTerm {
+	wrapper := NewWrapperTerm(term.GetOperator(), &composite{Name: name, Term: term})
+	return markStrict(withPolicy(wrapper, policyOf(term)), isStrict(term))
+}
+
+// ExpandComposites replaces the composite connectables of the term with the encapsulated terms recursively.
+// The named terms containing composites are replaced with their expanded definitions
+func ExpandComposites(term EquationTerm) EquationTerm {
+	if !hasComposites(term) {
+		return term
+	}
+
+	named, isNamed := term.(NamedTerm)
+	if isNamed {
+		return ExpandComposites(named.GetDefinition())
+	}
+	c, isComposite := compositeOf(term)
+	if isComposite {
+		return ExpandComposites(c.GetTerm())
+	}
+
+	processed := term.GetProcessedTerm()
+	if processed == nil {
+		return expandLeaf(term)
+	}
+	return applyOperation(
+		ExpandComposites(toTerm(processed.GetSource())),
+		processed,
+		ExpandComposites(toTerm(processed.GetSink())))
+}
+
+// implementation details
+
+type composite struct {
+	Name string
+	Term EquationTerm
+}
+
+func (c *composite) GetId() string {
+	return c.Name
+}
+
+func (c *composite) GetTerm() EquationTerm {
+	return c.Term
+}
+
+// compositeOf returns the composite wrapped by a leaf term
+func compositeOf(c Category) (Composite, bool) {
+	term, isTerm := c.(EquationTerm)
+	if !isTerm || term.GetProcessedTerm() != nil {
+		return nil, false
+	}
+	sources := c.GetSources().AsArray()
+	sinks := c.GetSinks().AsArray()
+	if len(sources) != 1 || len(sinks) != 1 {
+		return nil, false
+	}
+	found, isComposite := sources[0].(Composite)
+	return found, isComposite && sameObject(found, sinks[0])
+}
+
+func hasComposites(c Category) bool {
+	return len(collectComposites(c)) > 0
+}
+
+// collectComposites returns the composites found from the sets and the operations of the category
+func collectComposites(c Category) []Composite {
+	found := []Composite{}
+	add := func(connectable Connectable) {
+		c, isComposite := connectable.(Composite)
+		if !isComposite {
+			return
+		}
+		for _, existing := range found {
+			if sameObject(existing, c) {
+				return
+			}
+		}
+		found = append(found, c)
+	}
+
+	for _, connectable := range c.GetSources().AsArray() {
+		add(connectable)
+	}
+	for _, connectable := range c.GetSinks().AsArray() {
+		add(connectable)
+	}
+	for _, op := range c.GetOperations().AsArray() {
+		add(op.GetSource())
+		add(op.GetSink())
+	}
+	return found
+}
+
+// flattenOperations returns the operations to be evaluated: the connections of the composites are
+// moved to their boundaries and the operations of the encapsulated terms are added
+func flattenOperations(c Category) OperationSet {
+	composites := collectComposites(c)
+	if len(composites) == 0 {
+		return c.GetOperations()
+	}
+
+	policy := policyOf(c)
+	flattened := OperationSet(newOperationSet(c.GetOperator(), policy))
+	for _, op := range c.GetOperations().AsArray() {
+		for _, source := range boundary(op.GetSource(), true) {
+			for _, sink := range boundary(op.GetSink(), false) {
+				flattened.Add(&freezedOperation{
+					Source:     source,
+					Sink:       sink,
+					Operator:   op.GetOperator(),
+					Equaler:    policy.Identity,
+					Attributes: GetAttributes(op).Clone()})
+			}
+		}
+	}
+	for _, inner := range composites {
+		flattened = flattened.Union(flattenOperations(inner.GetTerm()))
+	}
+	return flattened
+}
+
+// expandLeaf returns a leaf term having the composites replaced with their boundaries and
+// the operations flattened, for the leaves made directly from the planned operations
+func expandLeaf(c Category) EquationTerm {
+	policy := policyOf(c)
+	sources := newConnectableSetFromArray(policy.Identity, []Connectable{})
+	for _, source := range c.GetSources().AsArray() {
+		for _, inner := range boundary(source, true) {
+			sources.Add(inner)
+		}
+	}
+	sinks := newConnectableSetFromArray(policy.Identity, []Connectable{})
+	for _, sink := range c.GetSinks().AsArray() {
+		for _, inner := range boundary(sink, false) {
+			sinks.Add(inner)
+		}
+	}
+
+	return markStrict(&equationTerm{
+		categoryImpl: categoryImpl{
+			Sources:    sources,
+			Sinks:      sinks,
+			Operator:   c.GetOperator(),
+			Operations: flattenOperations(c),
+			isZero:     c.IsZero(),
+			isIdentity: c.IsIdentity(),
+			stringImpl: func(*categoryImpl) string { return c.String() }},
+		processedTerm: nil}, isStrict(c))
+}
+
+// boundary returns the sources or the sinks a composite is replaced with on the connections
+func boundary(connectable Connectable, sources bool) []Connectable {
+	c, isComposite := connectable.(Composite)
+	if !isComposite {
+		return []Connectable{connectable}
+	}
+	set := c.GetTerm().GetSinks()
+	if sources {
+		set = c.GetTerm().GetSources()
+	}
+	found := []Connectable{}
+	for _, inner := range set.AsArray() {
+		found = append(found, boundary(inner, sources)...)
+	}
+	return found
+}
@@ -0,0 +1,137 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompositionMode selects how a CompositeOperator handles the failing operators
+type CompositionMode int
+
+const (
+	// AllOrNothing evaluates the operators in order. When one fails, the already succeeded operators
+	// implementing Disconnector are disconnected in the reverse order. Disconnect disconnects with all of them
+	AllOrNothing CompositionMode = iota
+	// BestEffort evaluates all the operators. The errors are returned only when none of them succeeds, so that
+	// the evaluation of the equation continues with the next connections. Disconnect disconnects with all of
+	// them using the same rule, because the failed operators did not connect anything
+	BestEffort
+	// FirstSuccess evaluates the operators in order until one of them succeeds. The operator having connected
+	// is not known later, so Disconnect disconnects with all of them like BestEffort. The Disconnector
+	// implementations should accept disconnecting the connections they have not made
+	FirstSuccess
+)
+
+// CompositeOperator is an Operator connecting each planned connection with several operators.
+// The attributes of the connections are passed to the operators implementing AttributedOperator.
+// Disconnect calls all the operators implementing Disconnector in the reverse order, the CompositionMode
+// decides when their errors are returned
+type CompositeOperator interface {
+	AttributedOperator
+	Disconnector
+	// GetOperators returns the combined operators
+	GetOperators() []Operator
+	// GetMode returns the composition mode
+	GetMode() CompositionMode
+}
+
+// CompositeOperatorError contains the errors of the failed operators of a CompositeOperator
+type CompositeOperatorError struct {
+	// Id is the id of the composite operator
+	Id string
+	// Errors are the errors in the order they happened, the failed rollbacks included
+	Errors []error
+}
+
+// Error lists the errors
+func (e *CompositeOperatorError) Error() string {
+	errors := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		errors[i] = err.Error()
+	}
+	return fmt.Sprintf("Operator %s failed: %s", e.Id, strings.Join(errors, "; "))
+}
+
+// NewCompositeOperator combines the operators under the given id
+func NewCompositeOperator(id string, mode CompositionMode, operators ...Operator) CompositeOperator {
+	return &compositeOperator{Id: id, Mode: mode, Operators: operators}
+}
+
+// implementation details
+
+type compositeOperator struct {
+	Id        string
+	Mode      CompositionMode
+	Operators []Operator
+}
+
+func (c *compositeOperator) GetId() string            { return c.Id }
+func (c *compositeOperator) GetMode() CompositionMode { return c.Mode }
+func (c *compositeOperator) GetOperators() []Operator { return c.Operators }
+func (c *compositeOperator) Evaluate(a Connectable, b Connectable) error {
+	return c.EvaluateWithAttributes(a, b, nil)
+}
+
+func (c *compositeOperator) EvaluateWithAttributes(a Connectable, b Connectable, attributes Attributes) error {
+	errors := []error{}
+	for i, operator := range c.Operators {
+		err := evaluateOperator(operator, a, b, attributes)
+		if err == nil {
+			if c.Mode == FirstSuccess {
+				return nil
+			}
+			continue
+		}
+
+		errors = append(errors, err)
+		if c.Mode == AllOrNothing {
+			errors = append(errors, disconnectOperators(c.Operators[:i], a, b)...)
+			break
+		}
+	}
+
+	if len(errors) == 0 || (c.Mode == BestEffort && len(errors) < len(c.Operators)) {
+		return nil
+	}
+	return &CompositeOperatorError{Id: c.Id, Errors: errors}
+}
+
+func (c *compositeOperator) Disconnect(a Connectable, b Connectable) error {
+	errors := disconnectOperators(c.Operators, a, b)
+	if len(errors) == 0 || (c.Mode != AllOrNothing && len(errors) < countDisconnectors(c.Operators)) {
+		return nil
+	}
+	return &CompositeOperatorError{Id: c.Id, Errors: errors}
+}
+
+func countDisconnectors(operators []Operator) int {
+	count := 0
+	for _, operator := range operators {
+		_, ok := operator.(Disconnector)
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+// disconnectOperators disconnects with the operators in the reverse order and returns the errors
+func disconnectOperators(operators []Operator, a Connectable, b Connectable) []error {
+	errors := []error{}
+	for i := len(operators) - 1; i >= 0; i-- {
+		disconnector, ok := operators[i].(Disconnector)
+		if !ok {
+			continue
+		}
+		err := disconnector.Disconnect(a, b)
+		if err != nil {
+			errors = append(errors, err)
+		}
+	}
+	return errors
+}
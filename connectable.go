@@ -22,6 +22,8 @@ type ConnectableSet interface {
 	Add(f Connectable)
 	// Remove removes and item from this set
 	Remove(f Connectable)
+	// Contains returns true, if the item is in this set
+	Contains(f Connectable) bool
 	// Equals is a set equality check
 	Equals(another ConnectableSet) bool
 	// AsArray returns the operations as an array
@@ -32,35 +34,40 @@ type ConnectableSet interface {
 	String() string
 }
 
-// NewConnectableSet creates a new ConnectableSet instance
+// NewConnectableSet creates a new ConnectableSet instance using the IdentityById policy
 func NewConnectableSet() ConnectableSet {
-	return newConnectableSetFromArray([]Connectable{})
+	return newConnectableSetFromArray(IdentityById, []Connectable{})
 }
 
 // implementation details
 
+// connectableSet keeps the connectables in buckets by their id. The identity policy decides,
+// which of the connectables having the same id are the same
 type connectableSet struct {
-	Connectables map[string]Connectable
+	Connectables map[string][]Connectable
+	Equaler      Equaler
 }
 
-func newConnectableSetFromArray(operations []Connectable) *connectableSet {
+func newConnectableSetFromArray(equaler Equaler, operations []Connectable) *connectableSet {
 	aSet := &connectableSet{
-		Connectables: make(map[string]Connectable, len(operations)),
+		Connectables: make(map[string][]Connectable, len(operations)),
+		Equaler:      equaler,
 	}
 	for _, v := range operations {
-		aSet.Connectables[v.GetId()] = v
+		aSet.Add(v)
 	}
 	return aSet
 }
 
 func (fs *connectableSet) Union(another ConnectableSet) ConnectableSet {
-	operations := another.AsArray()
+	unionSet := &connectableSet{Connectables: make(map[string][]Connectable), Equaler: fs.Equaler}
 
-	for _, v := range fs.Connectables {
-		operations = append(operations, v)
+	for _, v := range another.AsArray() {
+		unionSet.Add(v)
+	}
+	for _, v := range fs.AsArray() {
+		unionSet.Add(v)
 	}
-
-	unionSet := newConnectableSetFromArray(operations)
 
 	return unionSet
 }
@@ -76,52 +83,85 @@ func (fs *connectableSet) DiscardAll(another ConnectableSet) ConnectableSet {
 }
 
 func (fs *connectableSet) Clone() ConnectableSet {
-	freezeds := make(map[string]Connectable, len(fs.Connectables))
+	freezeds := make(map[string][]Connectable, len(fs.Connectables))
 
 	for k, v := range fs.Connectables {
-		freezeds[k] = v
+		freezeds[k] = append([]Connectable{}, v...)
 	}
 
-	return &connectableSet{Connectables: freezeds}
+	return &connectableSet{Connectables: freezeds, Equaler: fs.Equaler}
+}
+
+func (fs *connectableSet) find(f Connectable) int {
+	for i, v := range fs.Connectables[f.GetId()] {
+		if fs.Equaler.Equal(v, f) {
+			return i
+		}
+	}
+	return -1
 }
 
 func (fs *connectableSet) Add(f Connectable) {
-	fs.Connectables[f.GetId()] = f
+	i := fs.find(f)
+	if i < 0 {
+		fs.Connectables[f.GetId()] = append(fs.Connectables[f.GetId()], f)
+		return
+	}
+	fs.Connectables[f.GetId()][i] = f
 }
 
 func (fs *connectableSet) Remove(f Connectable) {
-	delete(fs.Connectables, f.GetId())
+	i := fs.find(f)
+	if i < 0 {
+		return
+	}
+	bucket := fs.Connectables[f.GetId()]
+	bucket = append(bucket[:i:i], bucket[i+1:]...)
+	if len(bucket) == 0 {
+		delete(fs.Connectables, f.GetId())
+		return
+	}
+	fs.Connectables[f.GetId()] = bucket
+}
+
+func (fs *connectableSet) Contains(f Connectable) bool {
+	return fs.find(f) >= 0
 }
 
 func (fs *connectableSet) Equals(another ConnectableSet) bool {
 	operations := another.AsArray()
 
-	if len(operations) != len(fs.Connectables) {
+	if len(operations) != fs.size() {
 		return false
 	}
 
 	for _, f := range operations {
-		_, found := fs.Connectables[f.GetId()]
-		if !found {
+		if !fs.Contains(f) {
 			return false
 		}
 	}
 	return true
 }
 
+func (fs *connectableSet) size() int {
+	size := 0
+	for _, v := range fs.Connectables {
+		size += len(v)
+	}
+	return size
+}
+
 func (fs *connectableSet) AsArray() []Connectable {
-	values := make([]Connectable, len(fs.Connectables))
-	i := 0
+	values := make([]Connectable, 0, fs.size())
 	for _, v := range fs.Connectables {
-		values[i] = v
-		i++
+		values = append(values, v...)
 	}
 	return values
 }
 
 func (fs *connectableSet) AsSortedArray() []Connectable {
 	arr := fs.AsArray()
-	sort.Slice(arr, func(i, j int) bool {
+	sort.SliceStable(arr, func(i, j int) bool {
 		cmpOp := strings.Compare(arr[i].GetId(), arr[j].GetId())
 		return cmpOp < 0
 	})
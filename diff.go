@@ -0,0 +1,167 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Diff is the structural difference between two categories. The attributes of the operations are not compared
+type Diff struct {
+	// FromOperator and ToOperator are the ids of the connecting operators of the categories
+	FromOperator string
+	ToOperator   string
+	// AddedSources and RemovedSources are the changed sources sorted by their ids
+	AddedSources   []Connectable
+	RemovedSources []Connectable
+	// AddedSinks and RemovedSinks are the changed sinks sorted by their ids
+	AddedSinks   []Connectable
+	RemovedSinks []Connectable
+	// AddedOperations and RemovedOperations are the changed operations in sorted order
+	AddedOperations   []FreezedOperation
+	RemovedOperations []FreezedOperation
+}
+
+// DiffChange is a single change of a connectable
+type DiffChange struct {
+	// Change is 'added' or 'removed'
+	Change string `json:"change"`
+	// Kind is 'source', 'sink' or 'operation'
+	Kind string `json:"kind"`
+	// Sink is the sink id of the changed operation
+	Sink string `json:"sink,omitempty"`
+	// Operator is the operator id of the changed operation
+	Operator string `json:"operator,omitempty"`
+	// Attributes are the attributes of the changed operation
+	Attributes Attributes `json:"attributes,omitempty"`
+}
+
+// DiffGroup contains the changes of a connectable. The operations are grouped by their sources
+type DiffGroup struct {
+	// Id is the id of the connectable
+	Id string `json:"id"`
+	// Changes are the changes of the connectable
+	Changes []DiffChange `json:"changes"`
+}
+
+// DiffError is returned by CheckEquals, when the categories differ
+type DiffError struct {
+	Diff *Diff
+}
+
+// Error prints the difference
+func (e *DiffError) Error() string {
+	return "Categories differ:\n" + e.Diff.String()
+}
+
+// Difference returns the changes needed to turn the category to the another
+func Difference(from Category, to Category) *Diff {
+	return &Diff{
+		FromOperator:      from.GetOperator().GetId(),
+		ToOperator:        to.GetOperator().GetId(),
+		AddedSources:      to.GetSources().DiscardAll(from.GetSources()).AsSortedArray(),
+		RemovedSources:    from.GetSources().DiscardAll(to.GetSources()).AsSortedArray(),
+		AddedSinks:        to.GetSinks().DiscardAll(from.GetSinks()).AsSortedArray(),
+		RemovedSinks:      from.GetSinks().DiscardAll(to.GetSinks()).AsSortedArray(),
+		AddedOperations:   to.GetOperations().DiscardAll(from.GetOperations()).AsSortedArray(),
+		RemovedOperations: from.GetOperations().DiscardAll(to.GetOperations()).AsSortedArray()}
+}
+
+// CheckEquals returns a *DiffError printing the difference, if the categories are not equal.
+// Use it on the tests instead of Equals to see what went wrong
+func CheckEquals(expected Category, actual Category) error {
+	diff := Difference(expected, actual)
+	if diff.IsEmpty() {
+		return nil
+	}
+	return &DiffError{Diff: diff}
+}
+
+// IsEmpty returns true, if there are no differences
+func (d *Diff) IsEmpty() bool {
+	return d.FromOperator == d.ToOperator && len(d.Groups()) == 0
+}
+
+// Groups returns the changes grouped by the connectables sorted by their ids
+func (d *Diff) Groups() []DiffGroup {
+	changes := make(map[string][]DiffChange)
+	addConnectables := func(connectables []Connectable, change string, kind string) {
+		for _, c := range connectables {
+			changes[c.GetId()] = append(changes[c.GetId()], DiffChange{Change: change, Kind: kind})
+		}
+	}
+	addOperations := func(operations []FreezedOperation, change string) {
+		for _, op := range operations {
+			id := op.GetSource().GetId()
+			changes[id] = append(changes[id], DiffChange{
+				Change:     change,
+				Kind:       "operation",
+				Sink:       op.GetSink().GetId(),
+				Operator:   op.GetOperator().GetId(),
+				Attributes: GetAttributes(op)})
+		}
+	}
+	addConnectables(d.AddedSources, "added", "source")
+	addConnectables(d.RemovedSources, "removed", "source")
+	addConnectables(d.AddedSinks, "added", "sink")
+	addConnectables(d.RemovedSinks, "removed", "sink")
+	addOperations(d.AddedOperations, "added")
+	addOperations(d.RemovedOperations, "removed")
+
+	groups := make([]DiffGroup, 0, len(changes))
+	for id, c := range changes {
+		groups = append(groups, DiffGroup{Id: id, Changes: c})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Id < groups[j].Id })
+	return groups
+}
+
+// String prints the difference in human readable form grouped by the connectables. Do not use for serialization.
+func (d *Diff) String() string {
+	lines := []string{}
+	if d.FromOperator != d.ToOperator {
+		lines = append(lines, fmt.Sprintf("operator: %s -> %s", d.FromOperator, d.ToOperator))
+	}
+	for _, group := range d.Groups() {
+		lines = append(lines, group.Id+":")
+		for _, change := range group.Changes {
+			sign := "+"
+			if change.Change == "removed" {
+				sign = "-"
+			}
+			if change.Kind != "operation" {
+				lines = append(lines, fmt.Sprintf("  %s %s", sign, change.Kind))
+				continue
+			}
+			line := fmt.Sprintf("  %s %s -> %s (%s)", sign, group.Id, change.Sink, change.Operator)
+			if len(change.Attributes) > 0 {
+				line += " " + change.Attributes.String()
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MarshalJSON serializes the difference grouped by the connectables:
+// {"operator":{"from":"a","to":"b"},"connectables":[{"id":"a","changes":[...]}]}
+func (d *Diff) MarshalJSON() ([]byte, error) {
+	type operatorChange struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	serialized := struct {
+		Operator     *operatorChange `json:"operator,omitempty"`
+		Connectables []DiffGroup     `json:"connectables"`
+	}{Connectables: d.Groups()}
+	if d.FromOperator != d.ToOperator {
+		serialized.Operator = &operatorChange{From: d.FromOperator, To: d.ToOperator}
+	}
+	return json.Marshal(serialized)
+}
@@ -0,0 +1,110 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"sort"
+	"strings"
+)
+
+// Component is a connectable representing a strongly connected component on the condensed category
+type Component struct {
+	// Members are the connectables of the component sorted by their ids
+	Members []category.Connectable
+}
+
+// GetId returns the id of the only member or the sorted member ids: '{a,b}'
+func (c *Component) GetId() string {
+	if len(c.Members) == 1 {
+		return c.Members[0].GetId()
+	}
+	ids := make([]string, len(c.Members))
+	for i, member := range c.Members {
+		ids[i] = member.GetId()
+	}
+	return "{" + strings.Join(ids, ",") + "}"
+}
+
+// StronglyConnectedComponents returns the strongly connected components of the planned operations,
+// see Graph.StronglyConnectedComponents
+func StronglyConnectedComponents(c category.Category) [][]category.Connectable {
+	return FromCategory(c).StronglyConnectedComponents()
+}
+
+// Condense returns the condensation of the category, see Graph.Condense
+func Condense(c category.Category) category.Category {
+	return FromCategory(c).Condense(c.GetOperator(), "condense("+c.String()+")")
+}
+
+// StronglyConnectedComponents returns the strongly connected components using the Tarjan's algorithm.
+// The components are in topological order and their members are sorted by their ids
+func (g *Graph) StronglyConnectedComponents() [][]category.Connectable {
+	found := tarjan(g, func(string) bool { return true })
+	components := make([][]category.Connectable, len(found))
+	for i, component := range found {
+		sort.Strings(component)
+		members := make([]category.Connectable, len(component))
+		for j, id := range component {
+			members[j], _ = g.Node(id)
+		}
+		components[len(found)-1-i] = members
+	}
+	return components
+}
+
+// Condense returns a new acyclic category having a Component connectable for each strongly connected component.
+// The operations between the components keep their operators and attributes, the operations inside the
+// components are left out. The components of the sources and the sinks are the sources and the sinks
+func (g *Graph) Condense(operator category.Operator, name string) category.Category {
+	componentOf := make(map[string]*Component)
+	for _, members := range g.StronglyConnectedComponents() {
+		component := &Component{Members: members}
+		for _, member := range members {
+			componentOf[member.GetId()] = component
+		}
+	}
+
+	sources := category.NewConnectableSet()
+	sinks := category.NewConnectableSet()
+	for _, id := range g.Nodes() {
+		if g.IsSource(id) {
+			sources.Add(componentOf[id])
+		}
+		if g.IsSink(id) {
+			sinks.Add(componentOf[id])
+		}
+	}
+
+	type condensedKey struct {
+		Source   *Component
+		Sink     *Component
+		Operator string
+	}
+	keys := []condensedKey{}
+	operators := make(map[condensedKey]category.Operator)
+	attributes := make(map[condensedKey]category.Attributes)
+	for _, e := range g.Edges() {
+		key := condensedKey{Source: componentOf[e.Source], Sink: componentOf[e.Sink], Operator: e.Operator}
+		if key.Source == key.Sink {
+			continue
+		}
+		_, found := operators[key]
+		if !found {
+			keys = append(keys, key)
+			operators[key] = e.Operation.GetOperator()
+			attributes[key] = category.GetAttributes(e.Operation)
+			continue
+		}
+		attributes[key] = category.MergeUnion.Merge(attributes[key], category.GetAttributes(e.Operation))
+	}
+
+	operations := category.NewOperationSet(operator)
+	for _, key := range keys {
+		operations.Add(category.NewAttributedOperation(operators[key], key.Source, key.Sink, attributes[key]))
+	}
+	return category.NewPlannedTerm(operator, sources, sinks, operations, name)
+}
@@ -0,0 +1,231 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CycleError is returned, when an equation containing cycles is refused
+type CycleError struct {
+	// Cycles are a sample of the cycles: the shortest cycle through the smallest id of each strongly
+	// connected component having cycles. Use FindCycles to get all of them
+	Cycles [][]category.Connectable
+}
+
+// Error lists the cycles: 'a -> b -> a'
+func (e *CycleError) Error() string {
+	cycles := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		cycles[i] = FormatCycle(cycle)
+	}
+	return fmt.Sprintf("Equation contains cycles: %s", strings.Join(cycles, "; "))
+}
+
+// FormatCycle prints the cycle returning back to its first connectable: 'a -> b -> a'
+func FormatCycle(cycle []category.Connectable) string {
+	ids := make([]string, 0, len(cycle)+1)
+	for _, connectable := range cycle {
+		ids = append(ids, connectable.GetId())
+	}
+	if len(cycle) > 0 {
+		ids = append(ids, cycle[0].GetId())
+	}
+	return strings.Join(ids, " -> ")
+}
+
+// FindCycles returns the elementary cycles of the planned operations, see Graph.FindCycles
+func FindCycles(c category.Category) [][]category.Connectable {
+	return FromCategory(c).FindCycles()
+}
+
+// IsAcyclic returns true, if the planned operations contain no cycles
+func IsAcyclic(c category.Category) bool {
+	return FromCategory(c).IsAcyclic()
+}
+
+// FindCycles returns the elementary cycles using the Johnson's algorithm. Each cycle starts from its
+// smallest id and the cycles are sorted. The number of the cycles may grow exponentially with the size
+// of the graph, use IsAcyclic when only their existence matters
+func (g *Graph) FindCycles() [][]category.Connectable {
+	nodes := g.Nodes()
+	order := make(map[string]int, len(nodes))
+	for i, id := range nodes {
+		order[id] = i
+	}
+
+	cycles := [][]category.Connectable{}
+	for i, start := range nodes {
+		var component []string
+		for _, found := range tarjan(g, func(id string) bool { return order[id] >= i }) {
+			if contains(found, start) {
+				component = found
+			}
+		}
+		if !isCyclic(g, component) {
+			continue
+		}
+
+		j := &johnsonState{
+			Graph:     g,
+			Start:     start,
+			Component: make(map[string]bool),
+			Blocked:   make(map[string]bool),
+			B:         make(map[string]map[string]bool)}
+		for _, id := range component {
+			j.Component[id] = true
+		}
+		j.circuit(start)
+		cycles = append(cycles, j.Cycles...)
+	}
+
+	sort.Slice(cycles, func(i, j int) bool { return FormatCycle(cycles[i]) < FormatCycle(cycles[j]) })
+	return cycles
+}
+
+// IsAcyclic returns true, if the graph contains no cycles
+func (g *Graph) IsAcyclic() bool {
+	for _, component := range tarjan(g, func(string) bool { return true }) {
+		if isCyclic(g, component) {
+			return false
+		}
+	}
+	return true
+}
+
+// implementation details
+
+// newCycleError returns the error describing the cycles of the graph without enumerating all of them
+func newCycleError(g *Graph) *CycleError {
+	cycles := [][]category.Connectable{}
+	for _, component := range g.cyclicComponents() {
+		cycles = append(cycles, g.shortestCycle(component))
+	}
+	return &CycleError{Cycles: cycles}
+}
+
+// cyclicComponents returns the strongly connected components having cycles. The ids of the components
+// are sorted and the components are sorted by their smallest ids
+func (g *Graph) cyclicComponents() [][]string {
+	components := [][]string{}
+	for _, component := range tarjan(g, func(string) bool { return true }) {
+		if isCyclic(g, component) {
+			sorted := append([]string{}, component...)
+			sort.Strings(sorted)
+			components = append(components, sorted)
+		}
+	}
+	sort.Slice(components, func(i, j int) bool { return components[i][0] < components[j][0] })
+	return components
+}
+
+// shortestCycle returns the shortest cycle through the first node of the component using a breadth first search
+func (g *Graph) shortestCycle(component []string) []category.Connectable {
+	start := component[0]
+	members := make(map[string]bool, len(component))
+	for _, id := range component {
+		members[id] = true
+	}
+
+	previous := make(map[string]string)
+	queue := []string{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range g.Successors(v) {
+			if w == start {
+				return g.tracePath(previous, start, v)
+			}
+			_, visited := previous[w]
+			if members[w] && !visited {
+				previous[w] = v
+				queue = append(queue, w)
+			}
+		}
+	}
+	return nil
+}
+
+// tracePath returns the connectables on the path from the start to the end using the previous nodes
+func (g *Graph) tracePath(previous map[string]string, start string, end string) []category.Connectable {
+	ids := []string{end}
+	for id := end; id != start; {
+		id = previous[id]
+		ids = append(ids, id)
+	}
+
+	path := make([]category.Connectable, len(ids))
+	for i, id := range ids {
+		path[len(ids)-1-i], _ = g.Node(id)
+	}
+	return path
+}
+
+type johnsonState struct {
+	Graph     *Graph
+	Start     string
+	Component map[string]bool
+	Blocked   map[string]bool
+	B         map[string]map[string]bool
+	Stack     []string
+	Cycles    [][]category.Connectable
+}
+
+func (j *johnsonState) circuit(v string) bool {
+	found := false
+	j.Stack = append(j.Stack, v)
+	j.Blocked[v] = true
+
+	for _, w := range j.Graph.Successors(v) {
+		if !j.Component[w] {
+			continue
+		}
+		if w == j.Start {
+			j.record()
+			found = true
+		} else if !j.Blocked[w] && j.circuit(w) {
+			found = true
+		}
+	}
+
+	if found {
+		j.unblock(v)
+	} else {
+		for _, w := range j.Graph.Successors(v) {
+			if !j.Component[w] {
+				continue
+			}
+			if j.B[w] == nil {
+				j.B[w] = make(map[string]bool)
+			}
+			j.B[w][v] = true
+		}
+	}
+
+	j.Stack = j.Stack[:len(j.Stack)-1]
+	return found
+}
+
+func (j *johnsonState) unblock(v string) {
+	j.Blocked[v] = false
+	for w := range j.B[v] {
+		delete(j.B[v], w)
+		if j.Blocked[w] {
+			j.unblock(w)
+		}
+	}
+}
+
+func (j *johnsonState) record() {
+	cycle := make([]category.Connectable, len(j.Stack))
+	for i, id := range j.Stack {
+		cycle[i], _ = j.Graph.Node(id)
+	}
+	j.Cycles = append(j.Cycles, cycle)
+}
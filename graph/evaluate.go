@@ -0,0 +1,86 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"fmt"
+	"strings"
+)
+
+// EvaluateOption is used to select optional behaviour for Evaluate
+type EvaluateOption int
+
+const (
+	// RefuseCycles makes Evaluate to return a *CycleError without connecting anything, when the planned
+	// operations contain cycles. The composites are expanded first, because their operations are evaluated too
+	RefuseCycles EvaluateOption = iota
+	// Layered evaluates the operations layer by layer, see Layers. The composites are expanded first.
+	// Implies RefuseCycles
+	Layered
+	// Parallel evaluates the operations of a layer concurrently and waits for all of them to finish before
+	// the next layer. The operators must be safe for concurrent use. Implies Layered
+	Parallel
+)
+
+// Evaluate connects the planned connections of the category in alphabetical order like
+// Category.EvaluateSorted using the given options
+func Evaluate(c category.Category, options ...EvaluateOption) error {
+	refuse, layered, parallel := false, false, false
+	for _, option := range options {
+		switch option {
+		case RefuseCycles:
+			refuse = true
+		case Layered:
+			layered = true
+		case Parallel:
+			layered, parallel = true, true
+		}
+	}
+	if !refuse && !layered {
+		return c.EvaluateSorted()
+	}
+
+	expanded, err := expand(c)
+	if err != nil {
+		return err
+	}
+	if !layered {
+		g := FromCategory(expanded)
+		if !g.IsAcyclic() {
+			return newCycleError(g)
+		}
+		return c.EvaluateSorted()
+	}
+
+	planned := category.NewPlannedTerm(
+		expanded.GetOperator(), category.NewConnectableSet(), category.NewConnectableSet(), expanded.GetOperations(), "")
+	err = category.ValidatePorts(planned)
+	if err != nil {
+		return err
+	}
+	layers, err := Layers(expanded)
+	if err != nil {
+		return err
+	}
+	return evaluateLayers(layers, parallel)
+}
+
+// implementation details
+
+// expand returns the category having its composites expanded, because the cycles and the layers are
+// decided by the operations which are evaluated
+func expand(c category.Category) (category.EquationTerm, error) {
+	term, isTerm := c.(category.EquationTerm)
+	if !isTerm {
+		term = category.NewPlannedTerm(c.GetOperator(), c.GetSources(), c.GetSinks(), c.GetOperations(), c.String())
+	}
+	free := category.FreeVars(term)
+	if len(free) > 0 {
+		return nil, fmt.Errorf("Unbound variables: %s", strings.Join(free, ", "))
+	}
+	return category.ExpandComposites(term), nil
+}
@@ -0,0 +1,207 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+// Package graph contains a directed graph view over the planned connection operations of a category
+// and the analysis tools built on it. The nodes are keyed by the connectable ids
+package graph
+
+import (
+	"category"
+	"sort"
+)
+
+// Edge is a planned connection operation from the source node to the sink node
+type Edge struct {
+	// Source is the id of the source node
+	Source string
+	// Sink is the id of the sink node
+	Sink string
+	// Operator is the id of the operator of the operation
+	Operator string
+	// Operation is the planned connection operation
+	Operation category.FreezedOperation
+}
+
+// Graph is a read only adjacency view over the operations, sources and sinks of a category
+type Graph struct {
+	nodes   map[string]category.Connectable
+	sources map[string]bool
+	sinks   map[string]bool
+	edges   []Edge
+	out     map[string][]Edge
+	in      map[string][]Edge
+}
+
+// FromCategory builds the graph of the category. The sources, the sinks and the connected connectables
+// are the nodes and the operations are the edges
+func FromCategory(c category.Category) *Graph {
+	g := newGraph()
+	for _, connectable := range c.GetSources().AsArray() {
+		g.addNode(connectable)
+		g.sources[connectable.GetId()] = true
+	}
+	for _, connectable := range c.GetSinks().AsArray() {
+		g.addNode(connectable)
+		g.sinks[connectable.GetId()] = true
+	}
+	for _, op := range c.GetOperations().AsSortedArray() {
+		g.addNode(op.GetSource())
+		g.addNode(op.GetSink())
+		g.addEdge(Edge{
+			Source:    op.GetSource().GetId(),
+			Sink:      op.GetSink().GetId(),
+			Operator:  op.GetOperator().GetId(),
+			Operation: op})
+	}
+	return g
+}
+
+// Nodes returns the sorted node ids
+func (g *Graph) Nodes() []string {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Node returns the connectable of the node and false, if there is no such node
+func (g *Graph) Node(id string) (category.Connectable, bool) {
+	connectable, found := g.nodes[id]
+	return connectable, found
+}
+
+// HasNode returns true, if the graph has the node
+func (g *Graph) HasNode(id string) bool {
+	_, found := g.nodes[id]
+	return found
+}
+
+// IsSource returns true, if the node is one of the sources of the category
+func (g *Graph) IsSource(id string) bool {
+	return g.sources[id]
+}
+
+// IsSink returns true, if the node is one of the sinks of the category
+func (g *Graph) IsSink(id string) bool {
+	return g.sinks[id]
+}
+
+// Edges returns the edges sorted by the operator, the source and the sink ids like OperationSet.AsSortedArray.
+// When operator ids are given, only the edges of those operators are returned
+func (g *Graph) Edges(operators ...string) []Edge {
+	return filterEdges(g.edges, operators)
+}
+
+// Out returns the edges starting from the node, optionally filtered by the operator ids
+func (g *Graph) Out(id string, operators ...string) []Edge {
+	return filterEdges(g.out[id], operators)
+}
+
+// In returns the edges ending to the node, optionally filtered by the operator ids
+func (g *Graph) In(id string, operators ...string) []Edge {
+	return filterEdges(g.in[id], operators)
+}
+
+// OutDegree returns the number of edges starting from the node, optionally filtered by the operator ids
+func (g *Graph) OutDegree(id string, operators ...string) int {
+	return len(g.Out(id, operators...))
+}
+
+// InDegree returns the number of edges ending to the node, optionally filtered by the operator ids
+func (g *Graph) InDegree(id string, operators ...string) int {
+	return len(g.In(id, operators...))
+}
+
+// Degree returns the sum of the in and out degrees of the node
+func (g *Graph) Degree(id string, operators ...string) int {
+	return g.InDegree(id, operators...) + g.OutDegree(id, operators...)
+}
+
+// Successors returns the sorted ids of the nodes the node is connected to
+func (g *Graph) Successors(id string, operators ...string) []string {
+	return uniqueIds(g.Out(id, operators...), func(e Edge) string { return e.Sink })
+}
+
+// Predecessors returns the sorted ids of the nodes connected to the node
+func (g *Graph) Predecessors(id string, operators ...string) []string {
+	return uniqueIds(g.In(id, operators...), func(e Edge) string { return e.Source })
+}
+
+// Filter returns a new graph having all the nodes, but only the edges of the given operators
+func (g *Graph) Filter(operators ...string) *Graph {
+	filtered := newGraph()
+	for id, connectable := range g.nodes {
+		filtered.nodes[id] = connectable
+	}
+	for id := range g.sources {
+		filtered.sources[id] = true
+	}
+	for id := range g.sinks {
+		filtered.sinks[id] = true
+	}
+	for _, e := range g.Edges(operators...) {
+		filtered.addEdge(e)
+	}
+	return filtered
+}
+
+// implementation details
+
+func newGraph() *Graph {
+	return &Graph{
+		nodes:   make(map[string]category.Connectable),
+		sources: make(map[string]bool),
+		sinks:   make(map[string]bool),
+		out:     make(map[string][]Edge),
+		in:      make(map[string][]Edge)}
+}
+
+func (g *Graph) addNode(connectable category.Connectable) {
+	_, found := g.nodes[connectable.GetId()]
+	if !found {
+		g.nodes[connectable.GetId()] = connectable
+	}
+}
+
+// addEdge expects the edges in sorted order
+func (g *Graph) addEdge(e Edge) {
+	g.edges = append(g.edges, e)
+	g.out[e.Source] = append(g.out[e.Source], e)
+	g.in[e.Sink] = append(g.in[e.Sink], e)
+}
+
+func filterEdges(edges []Edge, operators []string) []Edge {
+	filtered := []Edge{}
+	for _, e := range edges {
+		if len(operators) == 0 || contains(operators, e.Operator) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func uniqueIds(edges []Edge, id func(e Edge) string) []string {
+	found := make(map[string]bool)
+	ids := []string{}
+	for _, e := range edges {
+		if !found[id(e)] {
+			found[id(e)] = true
+			ids = append(ids, id(e))
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
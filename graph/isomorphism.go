@@ -0,0 +1,162 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"sort"
+	"strings"
+)
+
+// Isomorphic returns a mapping from the ids of the category to the ids of the another, if the categories
+// have the same structure, see Graph.Isomorphic
+func Isomorphic(a category.Category, b category.Category) (map[string]string, bool) {
+	return FromCategory(a).Isomorphic(FromCategory(b))
+}
+
+// Isomorphic returns a mapping from the node ids of this graph to the node ids of the another, if the graphs
+// have the same structure. The ids are ignored, but the edge directions, the operator ids of the edges and
+// the source and sink roles of the nodes are respected. The attributes of the operations are ignored
+func (g *Graph) Isomorphic(another *Graph) (map[string]string, bool) {
+	if len(g.nodes) != len(another.nodes) || len(g.edges) != len(another.edges) {
+		return nil, false
+	}
+
+	signatures := make(map[string]string, len(g.nodes))
+	counts := make(map[string]int)
+	for _, id := range g.Nodes() {
+		signatures[id] = g.signature(id)
+		counts[signatures[id]]++
+	}
+	candidates := make(map[string][]string)
+	for _, id := range another.Nodes() {
+		signature := another.signature(id)
+		counts[signature]--
+		candidates[signature] = append(candidates[signature], id)
+	}
+	for _, count := range counts {
+		if count != 0 {
+			return nil, false
+		}
+	}
+
+	m := &matcher{
+		From:       g,
+		To:         another,
+		Order:      g.matchOrder(signatures),
+		Signatures: signatures,
+		Candidates: candidates,
+		Mapping:    make(map[string]string),
+		Used:       make(map[string]bool)}
+	if !m.match(0) {
+		return nil, false
+	}
+	return m.Mapping, true
+}
+
+// implementation details
+
+// signature describes the node by its roles and the operators of its edges
+func (g *Graph) signature(id string) string {
+	operators := func(edges []Edge) string {
+		ids := make([]string, len(edges))
+		for i, e := range edges {
+			ids[i] = e.Operator
+		}
+		sort.Strings(ids)
+		return strings.Join(ids, ",")
+	}
+	roles := ""
+	if g.IsSource(id) {
+		roles += "source"
+	}
+	if g.IsSink(id) {
+		roles += "sink"
+	}
+	return roles + "|" + operators(g.In(id)) + "|" + operators(g.Out(id)) + "|" + g.label(id, id)
+}
+
+// label returns the sorted operator ids of the edges from the node to the another
+func (g *Graph) label(from string, to string) string {
+	ids := []string{}
+	for _, e := range g.out[from] {
+		if e.Sink == to {
+			ids = append(ids, e.Operator)
+		}
+	}
+	return strings.Join(ids, ",")
+}
+
+// matchOrder orders the nodes so that the ones with the rarest signatures come first and
+// the neighbours of the already ordered nodes come next
+func (g *Graph) matchOrder(signatures map[string]string) []string {
+	frequency := make(map[string]int)
+	for _, signature := range signatures {
+		frequency[signature]++
+	}
+	nodes := g.Nodes()
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return frequency[signatures[nodes[i]]] < frequency[signatures[nodes[j]]]
+	})
+
+	order := []string{}
+	ordered := make(map[string]bool)
+	for _, start := range nodes {
+		queue := []string{start}
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			if ordered[v] {
+				continue
+			}
+			ordered[v] = true
+			order = append(order, v)
+			queue = append(queue, g.Successors(v)...)
+			queue = append(queue, g.Predecessors(v)...)
+		}
+	}
+	return order
+}
+
+type matcher struct {
+	From       *Graph
+	To         *Graph
+	Order      []string
+	Signatures map[string]string
+	Candidates map[string][]string
+	Mapping    map[string]string
+	Used       map[string]bool
+}
+
+func (m *matcher) match(i int) bool {
+	if i == len(m.Order) {
+		return true
+	}
+	v := m.Order[i]
+	for _, w := range m.Candidates[m.Signatures[v]] {
+		if m.Used[w] || !m.consistent(v, w) {
+			continue
+		}
+		m.Mapping[v] = w
+		m.Used[w] = true
+		if m.match(i + 1) {
+			return true
+		}
+		delete(m.Mapping, v)
+		m.Used[w] = false
+	}
+	return false
+}
+
+// consistent checks the edges between the node and the already mapped nodes
+func (m *matcher) consistent(v string, w string) bool {
+	for u, mapped := range m.Mapping {
+		if m.From.label(v, u) != m.To.label(w, mapped) || m.From.label(u, v) != m.To.label(mapped, w) {
+			return false
+		}
+	}
+	return true
+}
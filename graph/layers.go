@@ -0,0 +1,77 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"sync"
+)
+
+// Layers partitions the planned operations into layers, see Graph.Layers
+func Layers(c category.Category) ([][]category.FreezedOperation, error) {
+	return FromCategory(c).Layers()
+}
+
+// Layers partitions the operations into layers, where each operation depends only on the operations of
+// the earlier layers: an operation starting from a node is placed after all the operations leading to it.
+// The operations of a layer are in sorted order. A *CycleError is returned, when there are cycles
+func (g *Graph) Layers() ([][]category.FreezedOperation, error) {
+	if !g.IsAcyclic() {
+		return nil, newCycleError(g)
+	}
+
+	depth := make(map[string]int)
+	for _, id := range g.topologicalOrder() {
+		for _, successor := range g.Successors(id) {
+			if depth[id]+1 > depth[successor] {
+				depth[successor] = depth[id] + 1
+			}
+		}
+	}
+
+	layers := [][]category.FreezedOperation{}
+	for _, e := range g.edges {
+		for len(layers) <= depth[e.Source] {
+			layers = append(layers, []category.FreezedOperation{})
+		}
+		layers[depth[e.Source]] = append(layers[depth[e.Source]], e.Operation)
+	}
+	return layers, nil
+}
+
+// implementation details
+
+// evaluateLayers evaluates the layers in order. The first error stops the evaluation after its layer
+func evaluateLayers(layers [][]category.FreezedOperation, parallel bool) error {
+	for _, layer := range layers {
+		errors := make([]error, len(layer))
+		if parallel {
+			var wg sync.WaitGroup
+			for i, op := range layer {
+				wg.Add(1)
+				go func(i int, op category.FreezedOperation) {
+					defer wg.Done()
+					errors[i] = op.Evaluate()
+				}(i, op)
+			}
+			wg.Wait()
+		} else {
+			for i, op := range layer {
+				errors[i] = op.Evaluate()
+				if errors[i] != nil {
+					break
+				}
+			}
+		}
+
+		for _, err := range errors {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"sort"
+	"strings"
+)
+
+// Match finds the occurrences of the pattern equation in the target category. The pattern contains free
+// variables: '$a * ($b + $c) * $d'. Each returned binding maps the variable names to distinct connectables so
+// that the operations of the bound pattern are a subset of the operations of the target, the operator ids
+// included. The connectables of the pattern which are not variables must be found from the target by their ids.
+// The bindings are sorted by the bound ids in the order of the variable names
+func Match(pattern category.EquationTerm, target category.Category) ([]map[string]category.Connectable, error) {
+	names := category.FreeVars(pattern)
+	bindings := make(map[string]category.Category, len(names))
+	for _, name := range names {
+		bindings[name] = category.NewWrapperTerm(pattern.GetOperator(), &placeholder{Name: name})
+	}
+	bound, err := category.Bind(pattern, bindings)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &patternMatcher{
+		Pattern: FromCategory(bound),
+		Target:  FromCategory(target),
+		Mapping: make(map[string]string),
+		Used:    make(map[string]bool),
+		Found:   []map[string]category.Connectable{}}
+	for _, id := range p.Pattern.Nodes() {
+		node, _ := p.Pattern.Node(id)
+		if _, isVariable := node.(*placeholder); isVariable {
+			continue
+		}
+		if !p.Target.HasNode(id) {
+			return p.Found, nil
+		}
+		p.Mapping[id] = id
+		p.Used[id] = true
+	}
+	for _, id := range p.Pattern.Nodes() {
+		_, isMapped := p.Mapping[id]
+		if !isMapped {
+			continue
+		}
+		if !p.consistent(id, id) {
+			return p.Found, nil
+		}
+	}
+
+	order := []string{}
+	for _, id := range p.Pattern.matchOrder(map[string]string{}) {
+		_, isMapped := p.Mapping[id]
+		if !isMapped {
+			order = append(order, id)
+		}
+	}
+	for _, name := range names {
+		if !p.Pattern.HasNode(variableId(name)) {
+			order = append(order, variableId(name))
+		}
+	}
+	p.Order = order
+	p.match(0)
+
+	key := func(binding map[string]category.Connectable) string {
+		ids := make([]string, len(names))
+		for i, name := range names {
+			ids[i] = binding[name].GetId()
+		}
+		return strings.Join(ids, "\x00")
+	}
+	sort.Slice(p.Found, func(i, j int) bool { return key(p.Found[i]) < key(p.Found[j]) })
+	return p.Found, nil
+}
+
+// implementation details
+
+// placeholder is the connectable a pattern variable is bound to, when the pattern operations are computed
+type placeholder struct {
+	Name string
+}
+
+func (p *placeholder) GetId() string {
+	return variableId(p.Name)
+}
+
+func variableId(name string) string {
+	return "$" + name
+}
+
+type patternMatcher struct {
+	Pattern *Graph
+	Target  *Graph
+	Order   []string
+	Mapping map[string]string
+	Used    map[string]bool
+	Found   []map[string]category.Connectable
+}
+
+func (p *patternMatcher) match(i int) {
+	if i == len(p.Order) {
+		p.record()
+		return
+	}
+	v := p.Order[i]
+	for _, w := range p.Target.Nodes() {
+		if p.Used[w] || !p.consistent(v, w) {
+			continue
+		}
+		p.Mapping[v] = w
+		p.Used[w] = true
+		p.match(i + 1)
+		delete(p.Mapping, v)
+		p.Used[w] = false
+	}
+}
+
+// consistent checks that the target has the pattern edges between the node and the already mapped nodes
+func (p *patternMatcher) consistent(v string, w string) bool {
+	if !p.covers(v, v, w, w) {
+		return false
+	}
+	for u, mapped := range p.Mapping {
+		if !p.covers(v, u, w, mapped) || !p.covers(u, v, mapped, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// covers returns true, if the target has all the pattern edges from the node to the another
+func (p *patternMatcher) covers(from string, to string, targetFrom string, targetTo string) bool {
+	for _, e := range p.Pattern.out[from] {
+		if e.Sink != to {
+			continue
+		}
+		if !contains(strings.Split(p.Target.label(targetFrom, targetTo), ","), e.Operator) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *patternMatcher) record() {
+	binding := make(map[string]category.Connectable)
+	for v, w := range p.Mapping {
+		node, _ := p.Pattern.Node(v)
+		variable, isVariable := node.(*placeholder)
+		if !isVariable {
+			continue
+		}
+		binding[variable.Name], _ = p.Target.Node(w)
+	}
+	for _, id := range p.Order {
+		if !p.Pattern.HasNode(id) {
+			binding[strings.TrimPrefix(id, "$")], _ = p.Target.Node(p.Mapping[id])
+		}
+	}
+	p.Found = append(p.Found, binding)
+}
@@ -0,0 +1,149 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+)
+
+// Reachable returns true, if there is a path from the node to the another, see Graph.Reachable
+func Reachable(c category.Category, from string, to string, operators ...string) bool {
+	return FromCategory(c).Reachable(from, to, operators...)
+}
+
+// ShortestPath returns a path with the fewest edges, see Graph.ShortestPath
+func ShortestPath(c category.Category, from string, to string, operators ...string) ([]category.Connectable, bool) {
+	return FromCategory(c).ShortestPath(from, to, operators...)
+}
+
+// AllSimplePaths returns the paths without repeated nodes, see Graph.AllSimplePaths
+func AllSimplePaths(c category.Category, from string, to string, limit int, operators ...string) [][]category.Connectable {
+	return FromCategory(c).AllSimplePaths(from, to, limit, operators...)
+}
+
+// Upstream returns the connectables having a path to the node, see Graph.Upstream
+func Upstream(c category.Category, node string, operators ...string) category.ConnectableSet {
+	return FromCategory(c).Upstream(node, operators...)
+}
+
+// Downstream returns the connectables the node has a path to, see Graph.Downstream
+func Downstream(c category.Category, node string, operators ...string) category.ConnectableSet {
+	return FromCategory(c).Downstream(node, operators...)
+}
+
+// Reachable returns true, if there is a path from the node to the another using only the edges of
+// the given operators. A node reaches itself
+func (g *Graph) Reachable(from string, to string, operators ...string) bool {
+	_, found := g.ShortestPath(from, to, operators...)
+	return found
+}
+
+// ShortestPath returns a path with the fewest edges from the node to the another using only the edges of
+// the given operators. The path contains the both ends and false is returned, if there is no path
+func (g *Graph) ShortestPath(from string, to string, operators ...string) ([]category.Connectable, bool) {
+	if !g.HasNode(from) || !g.HasNode(to) {
+		return nil, false
+	}
+
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 && queue[0] != to {
+		v := queue[0]
+		queue = queue[1:]
+		for _, w := range g.Successors(v, operators...) {
+			_, visited := previous[w]
+			if !visited {
+				previous[w] = v
+				queue = append(queue, w)
+			}
+		}
+	}
+	if len(queue) == 0 {
+		return nil, false
+	}
+
+	path := []category.Connectable{}
+	for id := to; ; id = previous[id] {
+		node, _ := g.Node(id)
+		path = append([]category.Connectable{node}, path...)
+		if id == from {
+			break
+		}
+	}
+	return path, true
+}
+
+// AllSimplePaths returns the paths without repeated nodes from the node to the another using only the edges
+// of the given operators. At most limit paths are returned, when the limit is positive.
+// The number of the paths may grow exponentially with the size of the graph
+func (g *Graph) AllSimplePaths(from string, to string, limit int, operators ...string) [][]category.Connectable {
+	paths := [][]category.Connectable{}
+	if !g.HasNode(from) || !g.HasNode(to) {
+		return paths
+	}
+
+	onPath := make(map[string]bool)
+	var visit func(path []string)
+	visit = func(path []string) {
+		if limit > 0 && len(paths) >= limit {
+			return
+		}
+		v := path[len(path)-1]
+		if v == to {
+			paths = append(paths, g.connectables(path))
+			return
+		}
+		onPath[v] = true
+		for _, w := range g.Successors(v, operators...) {
+			if !onPath[w] {
+				visit(append(path[:len(path):len(path)], w))
+			}
+		}
+		onPath[v] = false
+	}
+	visit([]string{from})
+	return paths
+}
+
+// Upstream returns the connectables having a path to the node using only the edges of the given operators.
+// The node itself is included only when it is on a cycle
+func (g *Graph) Upstream(node string, operators ...string) category.ConnectableSet {
+	return g.traverse(node, func(id string) []string { return g.Predecessors(id, operators...) })
+}
+
+// Downstream returns the connectables the node has a path to using only the edges of the given operators.
+// The node itself is included only when it is on a cycle
+func (g *Graph) Downstream(node string, operators ...string) category.ConnectableSet {
+	return g.traverse(node, func(id string) []string { return g.Successors(id, operators...) })
+}
+
+// implementation details
+
+func (g *Graph) connectables(ids []string) []category.Connectable {
+	connectables := make([]category.Connectable, len(ids))
+	for i, id := range ids {
+		connectables[i], _ = g.Node(id)
+	}
+	return connectables
+}
+
+func (g *Graph) traverse(node string, next func(id string) []string) category.ConnectableSet {
+	found := category.NewConnectableSet()
+	visited := make(map[string]bool)
+	stack := next(node)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		connectable, _ := g.Node(id)
+		found.Add(connectable)
+		stack = append(stack, next(id)...)
+	}
+	return found
+}
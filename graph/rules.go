@@ -0,0 +1,207 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule is a constraint for the planned operations of a category
+type Rule interface {
+	// GetName returns a short description of the rule: 'max fan-in 3'
+	GetName() string
+	// Check returns the violations of the rule found from the graph
+	Check(g *Graph) []Violation
+}
+
+// Violation describes a broken rule
+type Violation struct {
+	// Rule is the name of the broken rule
+	Rule string
+	// Message describes the violation
+	Message string
+	// Operations are the offending operations, if any
+	Operations []category.FreezedOperation
+}
+
+// String prints the violation in human readable form. Do not use for serialization.
+func (v Violation) String() string {
+	operations := make([]string, len(v.Operations))
+	for i, op := range v.Operations {
+		operations[i] = formatEdge(op)
+	}
+	if len(operations) == 0 {
+		return fmt.Sprintf("%s: %s", v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s: %s [%s]", v.Rule, v.Message, strings.Join(operations, ", "))
+}
+
+// Validate checks the rules in order and returns all the found violations
+func Validate(c category.Category, rules ...Rule) []Violation {
+	g := FromCategory(c)
+	violations := []Violation{}
+	for _, rule := range rules {
+		violations = append(violations, rule.Check(g)...)
+	}
+	return violations
+}
+
+// NewRule makes a rule from the check function
+func NewRule(name string, check func(g *Graph) []Violation) Rule {
+	return &rule{Name: name, CheckFunc: check}
+}
+
+// MaxFanIn limits the number of the edges ending to a node, optionally counting only the edges of the operators
+func MaxFanIn(limit int, operators ...string) Rule {
+	return NewRule(fmt.Sprintf("max fan-in %d", limit), func(g *Graph) []Violation {
+		return checkDegrees(g, limit, "Fan-in", func(id string) []Edge { return g.In(id, operators...) })
+	})
+}
+
+// MaxFanOut limits the number of the edges starting from a node, optionally counting only the edges of the operators
+func MaxFanOut(limit int, operators ...string) Rule {
+	return NewRule(fmt.Sprintf("max fan-out %d", limit), func(g *Graph) []Violation {
+		return checkDegrees(g, limit, "Fan-out", func(id string) []Edge { return g.Out(id, operators...) })
+	})
+}
+
+// ForbiddenEdges forbids the edges from the nodes matching the source glob to the nodes matching the sink glob.
+// On the globs '*' matches any sequence of characters and '?' matches any single character: 'test/*'
+func ForbiddenEdges(sourceGlob string, sinkGlob string) Rule {
+	source, sink := compileGlob(sourceGlob), compileGlob(sinkGlob)
+	name := fmt.Sprintf("forbidden %s -> %s", sourceGlob, sinkGlob)
+	return NewRule(name, func(g *Graph) []Violation {
+		violations := []Violation{}
+		for _, e := range g.Edges() {
+			if source.MatchString(e.Source) && sink.MatchString(e.Sink) {
+				violations = append(violations, Violation{
+					Message:    fmt.Sprintf("Edge %s -> %s is forbidden", e.Source, e.Sink),
+					Operations: []category.FreezedOperation{e.Operation}})
+			}
+		}
+		return named(name, violations)
+	})
+}
+
+// RequiredEdge requires at least one edge from a node matching the source glob to a node matching the sink glob,
+// optionally using one of the operators. The globs are the same as on ForbiddenEdges
+func RequiredEdge(sourceGlob string, sinkGlob string, operators ...string) Rule {
+	source, sink := compileGlob(sourceGlob), compileGlob(sinkGlob)
+	name := fmt.Sprintf("required %s -> %s", sourceGlob, sinkGlob)
+	return NewRule(name, func(g *Graph) []Violation {
+		for _, e := range g.Edges(operators...) {
+			if source.MatchString(e.Source) && sink.MatchString(e.Sink) {
+				return []Violation{}
+			}
+		}
+		return []Violation{{Rule: name, Message: fmt.Sprintf("No edge %s -> %s", sourceGlob, sinkGlob)}}
+	})
+}
+
+// Acyclic forbids the cycles. Each strongly connected component having cycles is a violation having
+// the edges inside the component as its operations
+func Acyclic() Rule {
+	return NewRule("acyclic", func(g *Graph) []Violation {
+		violations := []Violation{}
+		for _, component := range g.cyclicComponents() {
+			members := make(map[string]bool, len(component))
+			for _, id := range component {
+				members[id] = true
+			}
+			operations := []category.FreezedOperation{}
+			for _, id := range component {
+				for _, e := range g.Out(id) {
+					if members[e.Sink] {
+						operations = append(operations, e.Operation)
+					}
+				}
+			}
+			violations = append(violations, Violation{
+				Message:    fmt.Sprintf("Cycles between %s", strings.Join(component, ", ")),
+				Operations: operations})
+		}
+		return named("acyclic", violations)
+	})
+}
+
+// AllowedOperators forbids the operations of the other operators. Each forbidden operator is a violation
+func AllowedOperators(operators ...string) Rule {
+	name := fmt.Sprintf("allowed operators %s", strings.Join(operators, ","))
+	return NewRule(name, func(g *Graph) []Violation {
+		violations := []Violation{}
+		found := make(map[string]int)
+		for _, e := range g.Edges() {
+			if contains(operators, e.Operator) {
+				continue
+			}
+			i, exists := found[e.Operator]
+			if !exists {
+				i = len(violations)
+				found[e.Operator] = i
+				violations = append(violations, Violation{
+					Message: fmt.Sprintf("Operator %s is not allowed", e.Operator)})
+			}
+			violations[i].Operations = append(violations[i].Operations, e.Operation)
+		}
+		return named(name, violations)
+	})
+}
+
+// implementation details
+
+type rule struct {
+	Name      string
+	CheckFunc func(g *Graph) []Violation
+}
+
+func (r *rule) GetName() string {
+	return r.Name
+}
+
+func (r *rule) Check(g *Graph) []Violation {
+	return r.CheckFunc(g)
+}
+
+// named sets the rule name of the violations
+func named(name string, violations []Violation) []Violation {
+	for i := range violations {
+		violations[i].Rule = name
+	}
+	return violations
+}
+
+func checkDegrees(g *Graph, limit int, kind string, edges func(id string) []Edge) []Violation {
+	violations := []Violation{}
+	for _, id := range g.Nodes() {
+		found := edges(id)
+		if len(found) <= limit {
+			continue
+		}
+		operations := make([]category.FreezedOperation, len(found))
+		for i, e := range found {
+			operations[i] = e.Operation
+		}
+		violations = append(violations, Violation{
+			Message:    fmt.Sprintf("%s of %s is %d, more than %d", kind, id, len(found), limit),
+			Operations: operations})
+	}
+	return named(fmt.Sprintf("max %s %d", strings.ToLower(kind), limit), violations)
+}
+
+// compileGlob converts the glob to an anchored regular expression
+func compileGlob(glob string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(glob)
+	pattern = strings.Replace(pattern, `\*`, ".*", -1)
+	pattern = strings.Replace(pattern, `\?`, ".", -1)
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+func formatEdge(op category.FreezedOperation) string {
+	return fmt.Sprintf("%s -> %s (%s)", op.GetSource().GetId(), op.GetSink().GetId(), op.GetOperator().GetId())
+}
@@ -0,0 +1,80 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+// implementation details
+
+// tarjan returns the strongly connected components of the graph restricted to the accepted nodes.
+// The components are in reverse topological order and their nodes are in the order they were found
+func tarjan(g *Graph, accept func(id string) bool) [][]string {
+	t := &tarjanState{
+		Graph:   g,
+		Accept:  accept,
+		Index:   make(map[string]int),
+		Low:     make(map[string]int),
+		OnStack: make(map[string]bool)}
+	for _, id := range g.Nodes() {
+		_, visited := t.Index[id]
+		if accept(id) && !visited {
+			t.connect(id)
+		}
+	}
+	return t.Components
+}
+
+type tarjanState struct {
+	Graph      *Graph
+	Accept     func(id string) bool
+	Counter    int
+	Index      map[string]int
+	Low        map[string]int
+	OnStack    map[string]bool
+	Stack      []string
+	Components [][]string
+}
+
+func (t *tarjanState) connect(v string) {
+	t.Index[v] = t.Counter
+	t.Low[v] = t.Counter
+	t.Counter++
+	t.Stack = append(t.Stack, v)
+	t.OnStack[v] = true
+
+	for _, w := range t.Graph.Successors(v) {
+		if !t.Accept(w) {
+			continue
+		}
+		_, visited := t.Index[w]
+		if !visited {
+			t.connect(w)
+			if t.Low[w] < t.Low[v] {
+				t.Low[v] = t.Low[w]
+			}
+		} else if t.OnStack[w] && t.Index[w] < t.Low[v] {
+			t.Low[v] = t.Index[w]
+		}
+	}
+
+	if t.Low[v] != t.Index[v] {
+		return
+	}
+	component := []string{}
+	for {
+		w := t.Stack[len(t.Stack)-1]
+		t.Stack = t.Stack[:len(t.Stack)-1]
+		t.OnStack[w] = false
+		component = append(component, w)
+		if w == v {
+			break
+		}
+	}
+	t.Components = append(t.Components, component)
+}
+
+// isCyclic returns true, if the component contains a cycle
+func isCyclic(g *Graph, component []string) bool {
+	return len(component) > 1 || contains(g.Successors(component[0]), component[0])
+}
@@ -0,0 +1,193 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package graph
+
+import (
+	"category"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Stats contains the summary statistics of the topology of a category
+type Stats struct {
+	// Nodes is the number of the nodes
+	Nodes int `json:"nodes"`
+	// Edges is the number of the edges
+	Edges int `json:"edges"`
+	// Sources and Sinks are the numbers of the sources and the sinks of the category
+	Sources int `json:"sources"`
+	Sinks   int `json:"sinks"`
+	// NodesPerOperator is the number of the nodes having edges of the operator by the operator ids
+	NodesPerOperator map[string]int `json:"nodesPerOperator"`
+	// EdgesPerOperator is the number of the edges by the operator ids
+	EdgesPerOperator map[string]int `json:"edgesPerOperator"`
+	// FanIn is the number of the nodes by their in degree
+	FanIn map[int]int `json:"fanIn"`
+	// FanOut is the number of the nodes by their out degree
+	FanOut map[int]int `json:"fanOut"`
+	// MaxFanIn and MaxFanOut are the largest in and out degrees
+	MaxFanIn  int `json:"maxFanIn"`
+	MaxFanOut int `json:"maxFanOut"`
+	// Roots is the number of the nodes without incoming edges
+	Roots int `json:"roots"`
+	// Leaves is the number of the nodes without outgoing edges
+	Leaves int `json:"leaves"`
+	// Acyclic is true, if there are no cycles
+	Acyclic bool `json:"acyclic"`
+	// LongestPath is the number of the edges on the longest path or -1, when there are cycles
+	LongestPath int `json:"longestPath"`
+	// Density is the number of the connected node pairs divided by the number of the possible ordered pairs
+	Density float64 `json:"density"`
+}
+
+// Statistics returns the statistics of the category, see Graph.Stats
+func Statistics(c category.Category) *Stats {
+	return FromCategory(c).Stats()
+}
+
+// Stats returns the statistics of the graph
+func (g *Graph) Stats() *Stats {
+	s := &Stats{
+		Nodes:            len(g.nodes),
+		Edges:            len(g.edges),
+		Sources:          len(g.sources),
+		Sinks:            len(g.sinks),
+		NodesPerOperator: make(map[string]int),
+		EdgesPerOperator: make(map[string]int),
+		FanIn:            make(map[int]int),
+		FanOut:           make(map[int]int),
+		Acyclic:          g.IsAcyclic(),
+		LongestPath:      -1}
+
+	operatorNodes := make(map[string]map[string]bool)
+	for _, e := range g.edges {
+		s.EdgesPerOperator[e.Operator]++
+		if operatorNodes[e.Operator] == nil {
+			operatorNodes[e.Operator] = make(map[string]bool)
+		}
+		operatorNodes[e.Operator][e.Source] = true
+		operatorNodes[e.Operator][e.Sink] = true
+	}
+	for operator, nodes := range operatorNodes {
+		s.NodesPerOperator[operator] = len(nodes)
+	}
+
+	pairs := 0
+	for _, id := range g.Nodes() {
+		in, out := g.InDegree(id), g.OutDegree(id)
+		s.FanIn[in]++
+		s.FanOut[out]++
+		if in > s.MaxFanIn {
+			s.MaxFanIn = in
+		}
+		if out > s.MaxFanOut {
+			s.MaxFanOut = out
+		}
+		if in == 0 {
+			s.Roots++
+		}
+		if out == 0 {
+			s.Leaves++
+		}
+		for _, successor := range g.Successors(id) {
+			if successor != id {
+				pairs++
+			}
+		}
+	}
+	if s.Nodes > 1 {
+		s.Density = float64(pairs) / float64(s.Nodes*(s.Nodes-1))
+	}
+	if s.Acyclic {
+		s.LongestPath = g.longestPath()
+	}
+	return s
+}
+
+// String prints the statistics in human readable form. Do not use for serialization.
+func (s *Stats) String() string {
+	lines := []string{
+		fmt.Sprintf("nodes: %d", s.Nodes),
+		fmt.Sprintf("edges: %d", s.Edges),
+		fmt.Sprintf("sources: %d", s.Sources),
+		fmt.Sprintf("sinks: %d", s.Sinks)}
+	for _, operator := range sortedKeys(s.EdgesPerOperator) {
+		lines = append(lines, fmt.Sprintf("operator %s: %d nodes, %d edges",
+			operator, s.NodesPerOperator[operator], s.EdgesPerOperator[operator]))
+	}
+	lines = append(lines,
+		fmt.Sprintf("fan-in: %s (max %d)", formatDistribution(s.FanIn), s.MaxFanIn),
+		fmt.Sprintf("fan-out: %s (max %d)", formatDistribution(s.FanOut), s.MaxFanOut),
+		fmt.Sprintf("roots: %d", s.Roots),
+		fmt.Sprintf("leaves: %d", s.Leaves))
+	if s.Acyclic {
+		lines = append(lines, fmt.Sprintf("longest path: %d", s.LongestPath))
+	} else {
+		lines = append(lines, "longest path: cyclic")
+	}
+	lines = append(lines, fmt.Sprintf("density: %.3f", s.Density))
+	return strings.Join(lines, "\n")
+}
+
+// JSON returns the statistics serialized as JSON
+func (s *Stats) JSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// implementation details
+
+// longestPath returns the number of the edges on the longest path of an acyclic graph
+func (g *Graph) longestPath() int {
+	length := make(map[string]int)
+	longest := 0
+	for _, id := range g.topologicalOrder() {
+		for _, successor := range g.Successors(id) {
+			if length[id]+1 > length[successor] {
+				length[successor] = length[id] + 1
+			}
+		}
+		if length[id] > longest {
+			longest = length[id]
+		}
+	}
+	return longest
+}
+
+// topologicalOrder returns the nodes of an acyclic graph in topological order
+func (g *Graph) topologicalOrder() []string {
+	order := []string{}
+	for _, component := range g.StronglyConnectedComponents() {
+		for _, member := range component {
+			order = append(order, member.GetId())
+		}
+	}
+	return order
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatDistribution prints the distribution sorted by the degree: '0:2 1:3'
+func formatDistribution(distribution map[int]int) string {
+	degrees := make([]int, 0, len(distribution))
+	for degree := range distribution {
+		degrees = append(degrees, degree)
+	}
+	sort.Ints(degrees)
+	counts := make([]string, len(degrees))
+	for i, degree := range degrees {
+		counts[i] = fmt.Sprintf("%d:%d", degree, distribution[degree])
+	}
+	return strings.Join(counts, " ")
+}
@@ -0,0 +1,117 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Equaler decides when two connectables are the same connectable. The connectables compared by it
+// always have the same id
+type Equaler interface {
+	// Equal returns true, if the connectables are the same
+	Equal(a Connectable, b Connectable) bool
+}
+
+// EqualerFunc adapts a function to the Equaler interface
+type EqualerFunc func(a Connectable, b Connectable) bool
+
+// Equal calls the function
+func (f EqualerFunc) Equal(a Connectable, b Connectable) bool {
+	return f(a, b)
+}
+
+var (
+	// IdentityById treats the connectables with the same id as the same connectable. This is the default policy
+	IdentityById Equaler = EqualerFunc(func(a Connectable, b Connectable) bool {
+		return a.GetId() == b.GetId()
+	})
+	// IdentityByPointer treats only the same object as the same connectable
+	IdentityByPointer Equaler = EqualerFunc(sameObject)
+)
+
+// IdCollision describes an id used by more than one object
+type IdCollision struct {
+	// Id is the colliding id
+	Id string
+	// Connectables are the distinct objects using the id
+	Connectables []Connectable
+}
+
+// FindIdCollisions returns the ids used by more than one object in the equation sorted by the id.
+// The terms the equation was built from are checked too, because the colliding objects may have
+// replaced each other on the result sets
+func FindIdCollisions(c Category) []IdCollision {
+	found := make(map[string][]Connectable)
+	collectIdCollisions(c, found)
+
+	collisions := []IdCollision{}
+	for id, connectables := range found {
+		if len(connectables) > 1 {
+			collisions = append(collisions, IdCollision{Id: id, Connectables: connectables})
+		}
+	}
+	sort.Slice(collisions, func(i, j int) bool { return collisions[i].Id < collisions[j].Id })
+	return collisions
+}
+
+// implementation details
+
+func sameObject(a Connectable, b Connectable) bool {
+	return sameValue(a, b)
+}
+
+// sameValue compares with == when possible. The value is checked instead of the type, because
+// a comparable struct may contain an interface holding an uncomparable value
+func sameValue(a interface{}, b interface{}) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if reflect.ValueOf(a).Comparable() && reflect.ValueOf(b).Comparable() {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func collectIdCollisions(c Category, found map[string][]Connectable) {
+	collectConnectables(c, found)
+
+	switch term := c.(type) {
+	case NamedTerm:
+		collectIdCollisions(term.GetDefinition(), found)
+	case EquationTerm:
+		processed := term.GetProcessedTerm()
+		if processed != nil {
+			collectIdCollisions(processed.GetSource(), found)
+			collectIdCollisions(processed.GetSink(), found)
+		}
+	}
+}
+
+// collectConnectables adds the distinct objects of the sets and the operations of the category by their ids
+func collectConnectables(c Category, found map[string][]Connectable) {
+	add := func(connectable Connectable) {
+		id := connectable.GetId()
+		for _, existing := range found[id] {
+			if sameObject(existing, connectable) {
+				return
+			}
+		}
+		found[id] = append(found[id], connectable)
+	}
+
+	for _, connectable := range c.GetSources().AsArray() {
+		add(connectable)
+	}
+	for _, connectable := range c.GetSinks().AsArray() {
+		add(connectable)
+	}
+	for _, op := range c.GetOperations().AsArray() {
+		add(op.GetSource())
+		add(op.GetSink())
+	}
+}
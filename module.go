@@ -0,0 +1,312 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NamedTerm is an equation term bound to a name in a Module. It behaves like its definition,
+// but is printed by its name
+type NamedTerm interface {
+	EquationTerm
+	// GetName returns the name used when printing this term
+	GetName() string
+	// GetQualifiedName returns the name prefixed with the name of the defining module: 'module.name'
+	GetQualifiedName() string
+	// GetDefinition returns the term currently bound to the name
+	GetDefinition() EquationTerm
+}
+
+// Module contains named definitions of equation terms: 'let frontend = (a + b) * c'
+type Module interface {
+	// GetName returns the name of this module
+	GetName() string
+	// Define binds the term to the name and returns the named term.
+	// Redefining an existing name is allowed as long as it does not create a cycle between the definitions.
+	// Definitions using the redefined name see the new definition, terms already built from them do not
+	Define(name string, term EquationTerm) (NamedTerm, error)
+	// Get returns a named term by its name. Definitions of the imported modules are found with 'module.name'
+	Get(name string) (NamedTerm, error)
+	// Names returns the sorted names defined in this module
+	Names() []string
+	// Import makes the definitions of the another module available as 'module.name'
+	Import(another Module) error
+	// Imports returns the imported modules
+	Imports() []Module
+}
+
+// NewModule creates a new empty Module
+func NewModule(name string) Module {
+	return &module{
+		Name:        name,
+		Definitions: make(map[string]EquationTerm),
+		Imported:    make(map[string]Module),
+		Cache:       make(map[string]cachedDefinition)}
+}
+
+// Expand replaces all the named terms from the term with their definitions recursively
+func Expand(term EquationTerm) EquationTerm {
+	named, isNamed := term.(NamedTerm)
+	if isNamed {
+		return Expand(named.GetDefinition())
+	}
+
+	processed := term.GetProcessedTerm()
+	if processed == nil {
+		return term
+	}
+
+	return applyOperation(
+		Expand(toTerm(processed.GetSource())),
+		processed,
+		Expand(toTerm(processed.GetSink())))
+}
+
+// implementation details
+
+type module struct {
+	Name        string
+	Definitions map[string]EquationTerm
+	Imported    map[string]Module
+	// Revision is bumped on every change, the refreshed definitions are cached by the version.
+	// The getters of the named terms use the cache, so it is locked to keep them safe for concurrent use
+	Revision  int
+	Cache     map[string]cachedDefinition
+	CacheLock sync.Mutex
+}
+
+type cachedDefinition struct {
+	Version int
+	Term    EquationTerm
+}
+
+func (m *module) GetName() string {
+	return m.Name
+}
+
+func (m *module) Define(name string, term EquationTerm) (NamedTerm, error) {
+	if name == "" || strings.Contains(name, ".") {
+		return nil, fmt.Errorf("Invalid definition name '%s'", name)
+	}
+	if term == nil {
+		return nil, fmt.Errorf("Definition '%s' has no term", name)
+	}
+
+	qualified := m.Name + "." + name
+	path := findDefinitionPath(term, qualified, []string{qualified}, make(map[string]bool))
+	if path != nil {
+		return nil, fmt.Errorf("Definition cycle: %s", strings.Join(path, " -> "))
+	}
+
+	m.Definitions[name] = term
+	m.Revision++
+	return m.newNamedTerm(name), nil
+}
+
+func (m *module) Get(name string) (NamedTerm, error) {
+	_, found := m.Definitions[name]
+	if found {
+		return m.newNamedTerm(name), nil
+	}
+
+	parts := strings.SplitN(name, ".", 2)
+	if len(parts) == 2 {
+		imported, found := m.Imported[parts[0]]
+		if found {
+			named, err := imported.Get(parts[1])
+			if err != nil {
+				return nil, err
+			}
+			return &namedTerm{
+				Name:          name,
+				QualifiedName: named.GetQualifiedName(),
+				Lookup:        named.GetDefinition}, nil
+		}
+	}
+	return nil, fmt.Errorf("Undefined name '%s' in module %s", name, m.Name)
+}
+
+func (m *module) Names() []string {
+	names := make([]string, 0, len(m.Definitions))
+	for k := range m.Definitions {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *module) Import(another Module) error {
+	if another == nil {
+		return fmt.Errorf("Importing nil module to %s", m.Name)
+	}
+	_, found := m.Imported[another.GetName()]
+	if found {
+		return fmt.Errorf("Module %s already imported to %s", another.GetName(), m.Name)
+	}
+	if importsModule(another, m.Name, make(map[string]bool)) {
+		return fmt.Errorf("Import cycle: %s -> %s", m.Name, another.GetName())
+	}
+	m.Imported[another.GetName()] = another
+	m.Revision++
+	return nil
+}
+
+func (m *module) Imports() []Module {
+	names := make([]string, 0, len(m.Imported))
+	for k := range m.Imported {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	imports := make([]Module, len(names))
+	for i, name := range names {
+		imports[i] = m.Imported[name]
+	}
+	return imports
+}
+
+func (m *module) newNamedTerm(name string) NamedTerm {
+	return &namedTerm{
+		Name:          name,
+		QualifiedName: m.Name + "." + name,
+		Lookup:        func() EquationTerm { return m.lookup(name) }}
+}
+
+// lookup returns the refreshed definition. It is rebuilt only when this module or
+// the imported ones have changed after the previous lookup. The lock is not held while rebuilding,
+// because the rebuilding looks up the other definitions
+func (m *module) lookup(name string) EquationTerm {
+	version := m.version()
+	m.CacheLock.Lock()
+	cached, found := m.Cache[name]
+	m.CacheLock.Unlock()
+	if found && cached.Version == version {
+		return cached.Term
+	}
+
+	term := refresh(m.Definitions[name])
+	m.CacheLock.Lock()
+	m.Cache[name] = cachedDefinition{Version: version, Term: term}
+	m.CacheLock.Unlock()
+	return term
+}
+
+// version changes whenever this module or any of the imported modules changes.
+// The imports can not have cycles and the revisions only grow, so their sum is enough
+func (m *module) version() int {
+	version := m.Revision
+	for _, imported := range m.Imported {
+		another, ok := imported.(*module)
+		if ok {
+			version += another.version()
+		}
+	}
+	return version
+}
+
+// refresh rebuilds the term so that the named terms in it are seen with their current definitions
+func refresh(term EquationTerm) EquationTerm {
+	_, isNamed := term.(NamedTerm)
+	processed := term.GetProcessedTerm()
+	if isNamed || processed == nil {
+		return term
+	}
+	return applyOperation(
+		refresh(toTerm(processed.GetSource())),
+		processed,
+		refresh(toTerm(processed.GetSink())))
+}
+
+func importsModule(m Module, name string, visited map[string]bool) bool {
+	if m.GetName() == name {
+		return true
+	}
+	if visited[m.GetName()] {
+		return false
+	}
+	visited[m.GetName()] = true
+	for _, imported := range m.Imports() {
+		if importsModule(imported, name, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// findDefinitionPath returns the chain of definitions leading from the term to the given qualified name or nil
+func findDefinitionPath(c Category, qualified string, path []string, visited map[string]bool) []string {
+	named, isNamed := c.(NamedTerm)
+	if isNamed {
+		path = append(path, named.GetQualifiedName())
+		if named.GetQualifiedName() == qualified {
+			return path
+		}
+		if visited[named.GetQualifiedName()] {
+			return nil
+		}
+		visited[named.GetQualifiedName()] = true
+		return findDefinitionPath(named.GetDefinition(), qualified, path, visited)
+	}
+
+	term, isTerm := c.(EquationTerm)
+	if !isTerm || term.GetProcessedTerm() == nil {
+		return nil
+	}
+	found := findDefinitionPath(term.GetProcessedTerm().GetSource(), qualified, path, visited)
+	if found != nil {
+		return found
+	}
+	return findDefinitionPath(term.GetProcessedTerm().GetSink(), qualified, path, visited)
+}
+
+type namedTerm struct {
+	Name          string
+	QualifiedName string
+	Lookup        func() EquationTerm
+}
+
+func (n *namedTerm) GetName() string                 { return n.Name }
+func (n *namedTerm) GetQualifiedName() string        { return n.QualifiedName }
+func (n *namedTerm) GetDefinition() EquationTerm     { return n.Lookup() }
+func (n *namedTerm) GetSources() ConnectableSet      { return n.Lookup().GetSources() }
+func (n *namedTerm) GetSinks() ConnectableSet        { return n.Lookup().GetSinks() }
+func (n *namedTerm) GetOperator() Operator           { return n.Lookup().GetOperator() }
+func (n *namedTerm) GetOperations() OperationSet     { return n.Lookup().GetOperations() }
+func (n *namedTerm) IsZero() bool                    { return n.Lookup().IsZero() }
+func (n *namedTerm) IsIdentity() bool                { return n.Lookup().IsIdentity() }
+func (n *namedTerm) Evaluate() error                 { return n.Lookup().Evaluate() }
+func (n *namedTerm) EvaluateSorted() error           { return n.Lookup().EvaluateSorted() }
+func (n *namedTerm) Teardown() error                 { return n.Lookup().Teardown() }
+func (n *namedTerm) String() string                  { return n.Name }
+func (n *namedTerm) GetProcessedTerm() ProcessedTerm { return nil }
+
+func (n *namedTerm) Equals(another Category) bool {
+	return n.Lookup().Equals(another)
+}
+
+func (n *namedTerm) Add(category Category) EquationTerm {
+	return addTerms(n, category)
+}
+
+func (n *namedTerm) Discard(category Category) EquationTerm {
+	return discardTerms(n, category)
+}
+
+func (n *namedTerm) Connect(anext Category) EquationTerm {
+	return connectTerms(n, n.GetOperator(), nil, anext)
+}
+
+func (n *namedTerm) ConnectWith(operator Operator, anext Category) EquationTerm {
+	return connectTerms(n, operator, nil, anext)
+}
+
+func (n *namedTerm) ConnectWithAttributes(attributes Attributes, anext Category) EquationTerm {
+	return connectTerms(n, n.GetOperator(), attributes, anext)
+}
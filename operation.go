@@ -6,7 +6,6 @@
 package category
 
 import (
-	"fmt"
 	"sort"
 	"strings"
 )
@@ -21,13 +20,13 @@ type FreezedOperation interface {
 	GetOperator() Operator
 	// Evaluate executes the planned connection operation, which may fail
 	Evaluate() error
-	// Equals returns true if the sink, source and operation are equal
+	// Equals returns true if the operator ids are equal and the sink and source are the same by the identity policy
 	Equals(another FreezedOperation) bool
 }
 
 // OperationSet contains a set of operations and a set of set operations for the operations set
 type OperationSet interface {
-	// Union is a set union. Returns a new set
+	// Union is a set union. Returns a new set. The attributes of the same operations are merged by the merge policy
 	Union(another OperationSet) OperationSet
 	// DiscardAll removes all instances of the another set from this one and returns it as a new
 	DiscardAll(another OperationSet) OperationSet
@@ -37,31 +36,58 @@ type OperationSet interface {
 	Add(f FreezedOperation)
 	// Remove removes and item from this set
 	Remove(f FreezedOperation)
-	// Equals is a set equality check
+	// Contains returns true, if the item is in this set
+	Contains(f FreezedOperation) bool
+	// Equals is a set equality check. The operators of the operations are compared, not the default operators.
+	// The attributes are not compared
 	Equals(another OperationSet) bool
 	// AsArray returns the operations as an array
 	AsArray() []FreezedOperation
 	// AsSortedArray returns the operations as a sorted array
 	AsSortedArray() []FreezedOperation
-	// GetOperator returns the connection operation
+	// GetOperator returns the default connection operation. The set may contain operations of other operators too
 	GetOperator() Operator
 }
 
-// NewFreezedOperation creates a new FreezedOperation instance
+// NewFreezedOperation creates a new FreezedOperation instance using the IdentityById policy
 func NewFreezedOperation(operator Operator, source Connectable, sink Connectable) FreezedOperation {
-	return &freezedOperation{Source: source, Sink: sink, Operator: operator}
+	return &freezedOperation{Source: source, Sink: sink, Operator: operator, Equaler: IdentityById}
 }
 
-// NewOperationSet creates a new OperationSet instance
+// NewOperationSet creates a new OperationSet instance using the IdentityById and MergeUnion policies
 func NewOperationSet(operator Operator) OperationSet {
+	return newOperationSet(operator, defaultPolicy)
+}
+
+// implementation details
+
+// setPolicy contains the policies of the sets. The terms carry them on their sets
+type setPolicy struct {
+	Identity Equaler
+	Merger   AttributeMerger
+}
+
+var defaultPolicy = setPolicy{Identity: IdentityById, Merger: MergeUnion}
+
+// policyOf returns the policies the term was made with. The terms combined from
+// the terms of different policies have the policies of the left operand
+func policyOf(c Category) setPolicy {
+	set, ok := c.GetOperations().(*operationSet)
+	if !ok {
+		return defaultPolicy
+	}
+	return setPolicy{Identity: set.Equaler, Merger: set.Merger}
+}
+
+func newOperationSet(operator Operator, policy setPolicy) *operationSet {
 	return &operationSet{
 		Operator:          operator,
-		FreezedOperations: make(map[freezedOperationKey]FreezedOperation),
+		FreezedOperations: make(map[freezedOperationKey][]FreezedOperation),
+		Equaler:           policy.Identity,
+		Merger:            policy.Merger,
 	}
 }
 
-// implementation details
-
 type freezedOperationKey struct {
 	Source   string
 	Sink     string
@@ -75,50 +101,67 @@ func getKey(op FreezedOperation) freezedOperationKey {
 		Operator: op.GetOperator().GetId()}
 }
 
+// sameOperation returns true, if the operations have the same operator id and the same source and sink by the policy
+func sameOperation(policy Equaler, op FreezedOperation, another FreezedOperation) bool {
+	return getKey(op) == getKey(another) &&
+		policy.Equal(op.GetSource(), another.GetSource()) && policy.Equal(op.GetSink(), another.GetSink())
+}
+
 type freezedOperation struct {
-	Source   Connectable
-	Sink     Connectable
-	Operator Operator
+	Source     Connectable
+	Sink       Connectable
+	Operator   Operator
+	Equaler    Equaler
+	Attributes Attributes
 }
 
 func (f *freezedOperation) GetSink() Connectable   { return f.Sink }
 func (f *freezedOperation) GetSource() Connectable { return f.Source }
 
 func (f *freezedOperation) Evaluate() error {
-	return f.Operator.Evaluate(f.Source, f.Sink)
+	return evaluateOperator(f.Operator, f.Source, f.Sink, f.Attributes)
+}
+
+// disconnect undoes the planned connection operation, if its operator is a Disconnector
+func disconnect(f FreezedOperation) error {
+	disconnector, ok := f.GetOperator().(Disconnector)
+	if !ok {
+		return nil
+	}
+	return disconnector.Disconnect(f.GetSource(), f.GetSink())
 }
 
+func (f *freezedOperation) GetAttributes() Attributes { return f.Attributes }
+
 func (f *freezedOperation) Equals(another FreezedOperation) bool {
-	return another != nil && f.Source == another.GetSource() && f.Sink == another.GetSink() && f.Operator.GetId() == another.GetOperator().GetId()
+	return another != nil && sameOperation(f.Equaler, f, another)
 }
 
 func (f *freezedOperation) GetOperator() Operator { return f.Operator }
 
+// operationSet keeps the operations in buckets by their source, sink and operator ids. The identity policy decides,
+// which of the operations having the same ids are the same
 type operationSet struct {
-	FreezedOperations map[freezedOperationKey]FreezedOperation
+	FreezedOperations map[freezedOperationKey][]FreezedOperation
 	Operator          Operator
+	Equaler           Equaler
+	Merger            AttributeMerger
 }
 
-func newOperationSetFromArray(operator Operator, operations []FreezedOperation) *operationSet {
-	aSet := &operationSet{
-		Operator:          operator,
-		FreezedOperations: make(map[freezedOperationKey]FreezedOperation, len(operations)),
-	}
-	for _, v := range operations {
-		aSet.FreezedOperations[getKey(v)] = v
-	}
-	return aSet
-}
-
+// Union keeps the operations of this set and merges the attributes of the same operations found from the another
 func (fs *operationSet) Union(another OperationSet) OperationSet {
-	operations := another.AsArray()
+	unionSet := fs.Clone().(*operationSet)
 
-	for _, v := range fs.FreezedOperations {
-		operations = append(operations, v)
+	for _, v := range another.AsArray() {
+		i := unionSet.find(v)
+		if i < 0 {
+			unionSet.Add(v)
+			continue
+		}
+		bucket := unionSet.FreezedOperations[getKey(v)]
+		bucket[i] = withAttributes(bucket[i], fs.Merger.Merge(GetAttributes(bucket[i]), GetAttributes(v)))
 	}
 
-	unionSet := newOperationSetFromArray(fs.Operator, operations)
-
 	return unionSet
 }
 
@@ -133,57 +176,87 @@ func (fs *operationSet) DiscardAll(another OperationSet) OperationSet {
 }
 
 func (fs *operationSet) Clone() OperationSet {
-	freezeds := make(map[freezedOperationKey]FreezedOperation, len(fs.FreezedOperations))
+	freezeds := make(map[freezedOperationKey][]FreezedOperation, len(fs.FreezedOperations))
 
 	for k, v := range fs.FreezedOperations {
-		freezeds[k] = v
+		freezeds[k] = append([]FreezedOperation{}, v...)
 	}
 
-	return &operationSet{Operator: fs.Operator, FreezedOperations: freezeds}
+	return &operationSet{Operator: fs.Operator, FreezedOperations: freezeds, Equaler: fs.Equaler, Merger: fs.Merger}
+}
+
+func (fs *operationSet) find(f FreezedOperation) int {
+	for i, v := range fs.FreezedOperations[getKey(f)] {
+		if sameOperation(fs.Equaler, v, f) {
+			return i
+		}
+	}
+	return -1
 }
 
 func (fs *operationSet) Add(f FreezedOperation) {
-	fs.FreezedOperations[getKey(f)] = f
+	key := getKey(f)
+	i := fs.find(f)
+	if i < 0 {
+		fs.FreezedOperations[key] = append(fs.FreezedOperations[key], f)
+		return
+	}
+	fs.FreezedOperations[key][i] = f
 }
 
 func (fs *operationSet) Remove(f FreezedOperation) {
-	fmt.Printf("%+v %+v\n", fs.FreezedOperations, f)
-	delete(fs.FreezedOperations, getKey(f))
+	key := getKey(f)
+	i := fs.find(f)
+	if i < 0 {
+		return
+	}
+	bucket := fs.FreezedOperations[key]
+	bucket = append(bucket[:i:i], bucket[i+1:]...)
+	if len(bucket) == 0 {
+		delete(fs.FreezedOperations, key)
+		return
+	}
+	fs.FreezedOperations[key] = bucket
 }
 
-func (fs *operationSet) Equals(another OperationSet) bool {
-	if !EqualOperators(fs.Operator, another.GetOperator()) {
-		return false
-	}
+func (fs *operationSet) Contains(f FreezedOperation) bool {
+	return fs.find(f) >= 0
+}
 
+func (fs *operationSet) Equals(another OperationSet) bool {
 	operations := another.AsArray()
 
-	if len(operations) != len(fs.FreezedOperations) {
+	if len(operations) != fs.size() {
 		return false
 	}
 
 	for _, f := range operations {
-		_, found := fs.FreezedOperations[getKey(f)]
-		if !found {
+		if !fs.Contains(f) {
 			return false
 		}
 	}
 	return true
 }
 
+func (fs *operationSet) size() int {
+	size := 0
+	for _, v := range fs.FreezedOperations {
+		size += len(v)
+	}
+	return size
+}
+
 func (fs *operationSet) AsArray() []FreezedOperation {
-	values := make([]FreezedOperation, len(fs.FreezedOperations))
-	i := 0
+	values := make([]FreezedOperation, 0, fs.size())
 	for _, v := range fs.FreezedOperations {
-		values[i] = v
-		i++
+		values = append(values, v...)
 	}
 	return values
 }
 
 func (fs *operationSet) AsSortedArray() []FreezedOperation {
 	arr := fs.AsArray()
-	sort.Slice(arr, func(i, j int) bool {
+	sort.SliceStable(arr, func(i, j int) bool {
 		cmpOp := strings.Compare(arr[i].GetOperator().GetId(), arr[j].GetOperator().GetId())
 		cmpSource := strings.Compare(arr[i].GetSource().GetId(), arr[j].GetSource().GetId())
 		cmpSink := strings.Compare(arr[i].GetSink().GetId(), arr[j].GetSink().GetId())
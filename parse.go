@@ -0,0 +1,256 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Parse reads an equation from its textual form, for example '(a + b) * c - I * O'.
+// The '*' binds tighter than '+' and '-', 'I' and 'O' are the identity and the zero terms,
+// identifiers starting with '$' are free variables and all the other identifiers are looked up from the names.
+// A connection using a non default operator is written as '*[id]', where id is the id of the factory's operator
+func Parse(factory EquationFactory, text string, names map[string]Category) (EquationTerm, error) {
+	return parse(&parser{Factory: factory, Text: []rune(text), Names: names})
+}
+
+// ParseRegistered reads an equation from its textual form like Parse does, but the identifiers are looked up
+// from the connectable registry and wrapped with the factory and the operators of '*[id]' are looked up
+// from the operator registry
+func ParseRegistered(
+	factory EquationFactory,
+	text string,
+	operators OperatorRegistry,
+	connectables ConnectableRegistry) (EquationTerm, error) {
+	return parse(&parser{Factory: factory, Text: []rune(text), Operators: operators, Connectables: connectables})
+}
+
+// ParseWithTemplates reads an equation from its textual form like Parse does, but the templates can be
+// instantiated with the call syntax: 'fanin(a + b, c)'. The arguments are bound to the template
+// parameters in their declaration order
+func ParseWithTemplates(
+	factory EquationFactory,
+	text string,
+	names map[string]Category,
+	templates map[string]Template) (EquationTerm, error) {
+	return parse(&parser{Factory: factory, Text: []rune(text), Names: names, Templates: templates})
+}
+
+// implementation details
+
+func parse(p *parser) (EquationTerm, error) {
+	text := string(p.Text)
+	term, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.Pos < len(p.Text) {
+		return nil, fmt.Errorf("Unexpected '%c' at %d in '%s'", p.Text[p.Pos], p.Pos, text)
+	}
+	return term, nil
+}
+
+type parser struct {
+	Factory      EquationFactory
+	Text         []rune
+	Pos          int
+	Names        map[string]Category
+	Operators    OperatorRegistry
+	Connectables ConnectableRegistry
+	Templates    map[string]Template
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '/' || r == ':'
+}
+
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if !isIdentifierRune(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *parser) skipSpaces() {
+	for p.Pos < len(p.Text) && unicode.IsSpace(p.Text[p.Pos]) {
+		p.Pos++
+	}
+}
+
+func (p *parser) peek() rune {
+	p.skipSpaces()
+	if p.Pos >= len(p.Text) {
+		return 0
+	}
+	return p.Text[p.Pos]
+}
+
+func (p *parser) parseSum() (EquationTerm, error) {
+	term, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		r := p.peek()
+		if r != '+' && r != '-' {
+			return term, nil
+		}
+		p.Pos++
+		another, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		if r == '+' {
+			term = term.Add(another)
+		} else {
+			term = term.Discard(another)
+		}
+	}
+}
+
+func (p *parser) parseProduct() (EquationTerm, error) {
+	term, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '*' {
+		p.Pos++
+		operator, err := p.parseOperator()
+		if err != nil {
+			return nil, err
+		}
+		another, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		term = term.ConnectWith(operator, another)
+	}
+	return term, nil
+}
+
+// parseOperator reads the optional '[id]' after '*'
+func (p *parser) parseOperator() (Operator, error) {
+	if p.Pos >= len(p.Text) || p.Text[p.Pos] != '[' {
+		return p.Factory.GetOperator(), nil
+	}
+	start := p.Pos + 1
+	for p.Pos < len(p.Text) && p.Text[p.Pos] != ']' {
+		p.Pos++
+	}
+	if p.Pos >= len(p.Text) {
+		return nil, fmt.Errorf("Expected ']' at %d in '%s'", p.Pos, string(p.Text))
+	}
+	id := string(p.Text[start:p.Pos])
+	p.Pos++
+
+	if id == p.Factory.GetOperator().GetId() {
+		return p.Factory.GetOperator(), nil
+	}
+	if p.Operators != nil {
+		operator, found := p.Operators.Lookup(id)
+		if found {
+			return operator, nil
+		}
+	}
+	return nil, fmt.Errorf("Undefined operator '%s' at %d in '%s'", id, start, string(p.Text))
+}
+
+func (p *parser) parseFactor() (EquationTerm, error) {
+	r := p.peek()
+	if r == '(' {
+		p.Pos++
+		term, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("Expected ')' at %d in '%s'", p.Pos, string(p.Text))
+		}
+		p.Pos++
+		return term, nil
+	}
+
+	variable := r == '$'
+	if variable {
+		p.Pos++
+	}
+
+	start := p.Pos
+	for p.Pos < len(p.Text) && isIdentifierRune(p.Text[p.Pos]) {
+		p.Pos++
+	}
+	if start == p.Pos {
+		return nil, fmt.Errorf("Expected a term at %d in '%s'", start, string(p.Text))
+	}
+
+	name := string(p.Text[start:p.Pos])
+	if variable {
+		return p.Factory.Var(name), nil
+	}
+	if p.peek() == '(' {
+		return p.parseCall(name, start)
+	}
+	switch name {
+	case "I":
+		return p.Factory.I(), nil
+	case "O":
+		return p.Factory.O(), nil
+	}
+	c, found := p.Names[name]
+	if !found && p.Connectables != nil {
+		connectable, isRegistered := p.Connectables.Lookup(name)
+		if isRegistered {
+			return p.Factory.W(connectable), nil
+		}
+	}
+	if !found || c == nil {
+		return nil, fmt.Errorf("Undefined name '%s' at %d in '%s'", name, start, string(p.Text))
+	}
+	return toTerm(c), nil
+}
+
+// parseCall reads the arguments of a template call and instantiates the template
+func (p *parser) parseCall(name string, start int) (EquationTerm, error) {
+	t, found := p.Templates[name]
+	if !found {
+		return nil, fmt.Errorf("Undefined template '%s' at %d in '%s'", name, start, string(p.Text))
+	}
+	p.Pos++
+
+	args := []Category{}
+	for p.peek() != ')' {
+		if len(args) > 0 {
+			if p.peek() != ',' {
+				return nil, fmt.Errorf("Expected ',' or ')' at %d in '%s'", p.Pos, string(p.Text))
+			}
+			p.Pos++
+		}
+		arg, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.Pos++
+
+	parameters := t.GetParameters()
+	if len(args) != len(parameters) {
+		return nil, fmt.Errorf("Template %s expects %d arguments, got %d at %d in '%s'",
+			name, len(parameters), len(args), start, string(p.Text))
+	}
+	bindings := make(map[string]Category, len(args))
+	for i, parameter := range parameters {
+		bindings[parameter] = args[i]
+	}
+	return t.Instantiate(bindings)
+}
@@ -0,0 +1,181 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+)
+
+// PortedConnectable is a connectable having named inputs and outputs. Wrap it with NewPortedWrapperTerm
+// to connect its output ports to the input ports of the next ones
+type PortedConnectable interface {
+	Connectable
+	// GetInputs returns the names of the input ports
+	GetInputs() []string
+	// GetOutputs returns the names of the output ports
+	GetOutputs() []string
+}
+
+// Port is a named input or output of a PortedConnectable. The ports are the connectables given to
+// Operator.Evaluate, when the ported terms are connected. Their id is 'owner.name'
+type Port interface {
+	Connectable
+	// GetOwner returns the connectable having this port
+	GetOwner() PortedConnectable
+	// GetName returns the name of the port
+	GetName() string
+	// IsOutput returns true for the output ports and false for the input ports
+	IsOutput() bool
+}
+
+// NewInputPort returns the named input port of the connectable
+func NewInputPort(owner PortedConnectable, name string) Port {
+	return port{Owner: owner, Name: name, Output: false}
+}
+
+// NewOutputPort returns the named output port of the connectable
+func NewOutputPort(owner PortedConnectable, name string) Port {
+	return port{Owner: owner, Name: name, Output: true}
+}
+
+// NewPortedWrapperTerm returns a new term wrapping the connectable. The output ports are its sources and
+// the input ports are its sinks
+func NewPortedWrapperTerm(operator Operator, connectable PortedConnectable) EquationTerm {
+	sources := NewConnectableSet()
+	for _, name := range connectable.GetOutputs() {
+		sources.Add(NewOutputPort(connectable, name))
+	}
+
+	sinks := NewConnectableSet()
+	for _, name := range connectable.GetInputs() {
+		sinks.Add(NewInputPort(connectable, name))
+	}
+
+	return newLeafTerm(operator, sources, sinks, connectable.GetId())
+}
+
+// TryPort wraps a single named port of the connectable into a term made by the factory. It returns an error
+// instead of panicking, if the connectable has no such port
+func TryPort(factory EquationFactory, c PortedConnectable, name string) (EquationTerm, error) {
+	_, err := lookupPort(c, name)
+	if err != nil {
+		return nil, err
+	}
+	return factory.Port(c, name), nil
+}
+
+// NewPortTerm returns a new term wrapping a single port. An output port is only a source and
+// an input port is only a sink
+func NewPortTerm(operator Operator, port Port) EquationTerm {
+	sources := NewConnectableSet()
+	sinks := NewConnectableSet()
+	if port.IsOutput() {
+		sources.Add(port)
+	} else {
+		sinks.Add(port)
+	}
+	return newLeafTerm(operator, sources, sinks, port.GetId())
+}
+
+// ValidatePorts returns an error, if an output port is used as a sink, an input port is used as a source
+// or a port is not declared by its owner. Reversing a ported equation makes it invalid
+func ValidatePorts(c Category) error {
+	err := validateOperationPorts(c.GetOperations())
+	if err != nil {
+		return err
+	}
+	for _, connectable := range c.GetSources().AsSortedArray() {
+		err := validatePort(connectable, true)
+		if err != nil {
+			return err
+		}
+	}
+	for _, connectable := range c.GetSinks().AsSortedArray() {
+		err := validatePort(connectable, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// implementation details
+
+type port struct {
+	Owner  PortedConnectable
+	Name   string
+	Output bool
+}
+
+func (p port) GetId() string {
+	return p.Owner.GetId() + "." + p.Name
+}
+
+func (p port) GetOwner() PortedConnectable {
+	return p.Owner
+}
+
+func (p port) GetName() string {
+	return p.Name
+}
+
+func (p port) IsOutput() bool {
+	return p.Output
+}
+
+// lookupPort finds the named port of the connectable, the outputs first
+func lookupPort(connectable PortedConnectable, name string) (Port, error) {
+	for _, output := range connectable.GetOutputs() {
+		if output == name {
+			return NewOutputPort(connectable, name), nil
+		}
+	}
+	for _, input := range connectable.GetInputs() {
+		if input == name {
+			return NewInputPort(connectable, name), nil
+		}
+	}
+	return nil, fmt.Errorf("Connectable '%s' has no port '%s'", connectable.GetId(), name)
+}
+
+// validateOperationPorts checks the ports of the operations. Evaluate and EvaluateSorted use it
+// to refuse connecting the ports the wrong way
+func validateOperationPorts(operations OperationSet) error {
+	for _, op := range operations.AsSortedArray() {
+		err := validatePort(op.GetSource(), true)
+		if err == nil {
+			err = validatePort(op.GetSink(), false)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validatePort checks the port direction, the other connectables are always valid
+func validatePort(connectable Connectable, asSource bool) error {
+	p, ok := connectable.(Port)
+	if !ok {
+		return nil
+	}
+	if asSource && !p.IsOutput() {
+		return fmt.Errorf("Input port '%s' is used as a source", p.GetId())
+	}
+	if !asSource && p.IsOutput() {
+		return fmt.Errorf("Output port '%s' is used as a sink", p.GetId())
+	}
+	names := p.GetOwner().GetInputs()
+	if p.IsOutput() {
+		names = p.GetOwner().GetOutputs()
+	}
+	for _, name := range names {
+		if name == p.GetName() {
+			return nil
+		}
+	}
+	return fmt.Errorf("Port '%s' is not declared by its owner", p.GetId())
+}
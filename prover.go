@@ -0,0 +1,331 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the algebraic laws used in the proofs
+const (
+	LawDefinition             = "definition"
+	LawLeftDistributivity     = "left distributivity"
+	LawRightDistributivity    = "right distributivity"
+	LawLeftIdentity           = "left identity"
+	LawRightIdentity          = "right identity"
+	LawZero                   = "zero is neutral for +"
+	LawAssociativityOfArrow   = "associativity of *"
+	LawAssociativityOfAdd     = "associativity of +"
+	LawCommutativityOfAdd     = "commutativity of +"
+	LawLeftCommutativityOfAdd = "left commutativity of +"
+	LawIdempotenceOfAdd       = "idempotence of +"
+	maxProofSteps             = 10000
+)
+
+// ProofStep is a single application of an algebraic law
+type ProofStep struct {
+	// Law is the name of the applied law
+	Law string
+	// Reversed is true when the law was applied from right to left
+	Reversed bool
+	// Term is the printed term after applying the law
+	Term string
+}
+
+// Counterexample tells what differs between two terms which are not equal
+type Counterexample struct {
+	// Operation is a planned connection operation found only from one of the terms. Nil if the operations are equal
+	Operation FreezedOperation
+	// Connectable is a source or a sink found only from one of the terms, when the operations are equal
+	Connectable Connectable
+	// Role is "operation", "source", "sink" or "operator" when only the operator ids differ
+	Role string
+	// InFirst is true when the difference is found from the first term and false when it is found from the second
+	InFirst bool
+}
+
+// Proof is a sequence of law applications transforming the first term into the second one
+type Proof struct {
+	// First is the printed form of the first term
+	First string
+	// Steps are the law applications leading to the second term
+	Steps []ProofStep
+	// Counterexample is set when the terms are not equal. Then there are no steps
+	Counterexample *Counterexample
+}
+
+// Prove searches for a sequence of algebraic laws transforming the first term into the second one.
+// Both of the terms are rewritten into a sum of products normal form and the two rewrite chains are joined.
+// When the terms are not equal, the returned proof contains a counterexample.
+// An error is returned when the terms are equal, but the laws can not show it (for example with discards)
+func Prove(first EquationTerm, second EquationTerm) (*Proof, error) {
+	if !first.Equals(second) {
+		return &Proof{First: first.String(), Counterexample: findCounterexample(first, second)}, nil
+	}
+
+	firstNormal, firstSteps, err := normalize(first)
+	if err != nil {
+		return nil, err
+	}
+	secondNormal, secondSteps, err := normalize(second)
+	if err != nil {
+		return nil, err
+	}
+	if firstNormal.String() != secondNormal.String() {
+		return nil, fmt.Errorf("No proof found: %s and %s are equal, but normalize to %s and %s",
+			first, second, firstNormal, secondNormal)
+	}
+
+	steps := firstSteps
+	for i := len(secondSteps) - 1; i >= 0; i-- {
+		term := second.String()
+		if i > 0 {
+			term = secondSteps[i-1].Term
+		}
+		steps = append(steps, ProofStep{Law: secondSteps[i].Law, Reversed: !secondSteps[i].Reversed, Term: term})
+	}
+	return &Proof{First: first.String(), Steps: steps}, nil
+}
+
+// String prints the proof in human readable form
+func (p *Proof) String() string {
+	if p.Counterexample != nil {
+		return p.First + "\n" + p.Counterexample.String()
+	}
+	lines := []string{p.First}
+	for _, step := range p.Steps {
+		law := step.Law
+		if step.Reversed {
+			law = law + " (reversed)"
+		}
+		lines = append(lines, fmt.Sprintf("= %s    [%s]", step.Term, law))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// String prints the counterexample in human readable form
+func (c *Counterexample) String() string {
+	where := "second"
+	if c.InFirst {
+		where = "first"
+	}
+	if c.Operation != nil {
+		return fmt.Sprintf("operation %s -> %s only in the %s term",
+			c.Operation.GetSource().GetId(), c.Operation.GetSink().GetId(), where)
+	}
+	if c.Connectable == nil {
+		return "the operators differ"
+	}
+	return fmt.Sprintf("%s %s only in the %s term", c.Role, c.Connectable.GetId(), where)
+}
+
+// implementation details
+
+func findCounterexample(first Category, second Category) *Counterexample {
+	operation := operationOnlyIn(first.GetOperations(), second.GetOperations())
+	if operation != nil {
+		return &Counterexample{Operation: operation, Role: "operation", InFirst: true}
+	}
+	operation = operationOnlyIn(second.GetOperations(), first.GetOperations())
+	if operation != nil {
+		return &Counterexample{Operation: operation, Role: "operation", InFirst: false}
+	}
+
+	sets := []struct {
+		role   string
+		first  ConnectableSet
+		second ConnectableSet
+	}{
+		{"source", first.GetSources(), second.GetSources()},
+		{"sink", first.GetSinks(), second.GetSinks()},
+	}
+	for _, s := range sets {
+		c := connectableOnlyIn(s.first, s.second)
+		if c != nil {
+			return &Counterexample{Connectable: c, Role: s.role, InFirst: true}
+		}
+		c = connectableOnlyIn(s.second, s.first)
+		if c != nil {
+			return &Counterexample{Connectable: c, Role: s.role, InFirst: false}
+		}
+	}
+	return &Counterexample{Role: "operator", InFirst: true}
+}
+
+func operationOnlyIn(set OperationSet, another OperationSet) FreezedOperation {
+	for _, op := range set.AsSortedArray() {
+		if !another.Contains(op) {
+			return op
+		}
+	}
+	return nil
+}
+
+func connectableOnlyIn(set ConnectableSet, another ConnectableSet) Connectable {
+	for _, c := range set.AsSortedArray() {
+		if !another.Contains(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// proofExpr is a processed term tree used for the rewriting
+type proofExpr struct {
+	Leaf       EquationTerm
+	Operation  Operation
+	Operator   Operator
+	Attributes Attributes
+	Source     *proofExpr
+	Sink       *proofExpr
+	text       string
+}
+
+func newProofExpr(c Category) *proofExpr {
+	term := toTerm(c)
+	if term.GetProcessedTerm() == nil {
+		return &proofExpr{Leaf: term}
+	}
+	processed := term.GetProcessedTerm()
+	return &proofExpr{
+		Operation:  processed.GetOperation(),
+		Operator:   processed.GetOperator(),
+		Attributes: processed.GetAttributes(),
+		Source:     newProofExpr(processed.GetSource()),
+		Sink:       newProofExpr(processed.GetSink())}
+}
+
+func newProofSum(source *proofExpr, sink *proofExpr) *proofExpr {
+	return &proofExpr{Operation: ADD, Source: source, Sink: sink}
+}
+
+// with returns a copy of the operation node with new operands
+func (e *proofExpr) with(source *proofExpr, sink *proofExpr) *proofExpr {
+	return &proofExpr{Operation: e.Operation, Operator: e.Operator, Attributes: e.Attributes, Source: source, Sink: sink}
+}
+
+// defaultOperator returns the operator the built term would have: the operator of the leftmost leaf
+func (e *proofExpr) defaultOperator() Operator {
+	if e.Leaf != nil {
+		return e.Leaf.GetOperator()
+	}
+	return e.Source.defaultOperator()
+}
+
+func (e *proofExpr) String() string {
+	if e.text == "" {
+		if e.Leaf != nil {
+			e.text = e.Leaf.String()
+		} else {
+			e.text = formatOperation(
+				e.Source.String(), e.Operation, e.Operator, e.Source.defaultOperator(), e.Attributes, e.Sink.String())
+		}
+	}
+	return e.text
+}
+
+func (e *proofExpr) is(operation Operation) bool {
+	return e.Leaf == nil && e.Operation == operation
+}
+
+func (e *proofExpr) isIdentityLeaf() bool {
+	return e.Leaf != nil && e.Leaf.IsIdentity()
+}
+
+func (e *proofExpr) isZeroLeaf() bool {
+	return e.Leaf != nil && e.Leaf.IsZero()
+}
+
+// identityNeutral tells if 'I * e' and 'e * I' behave exactly like e also in the later operations
+func (e *proofExpr) identityNeutral() bool {
+	return !e.isIdentityLeaf() && !e.isZeroLeaf() && !e.is(ADD)
+}
+
+func normalize(term EquationTerm) (*proofExpr, []ProofStep, error) {
+	steps := []ProofStep{}
+	expanded := Expand(term)
+	if expanded.String() != term.String() {
+		steps = append(steps, ProofStep{Law: LawDefinition, Term: expanded.String()})
+	}
+
+	e := newProofExpr(expanded)
+	for len(steps) < maxProofSteps {
+		rewritten, law := rewrite(e)
+		if rewritten == nil {
+			return e, steps, nil
+		}
+		e = rewritten
+		steps = append(steps, ProofStep{Law: law, Term: e.String()})
+	}
+	return nil, nil, fmt.Errorf("No normal form found for %s in %d steps", term, maxProofSteps)
+}
+
+// rewrite applies the first applicable law, outermost first. Returns nil when no law applies
+func rewrite(e *proofExpr) (*proofExpr, string) {
+	if e.Leaf != nil {
+		return nil, ""
+	}
+	rewritten, law := rewriteRoot(e)
+	if rewritten != nil {
+		return rewritten, law
+	}
+	source, law := rewrite(e.Source)
+	if source != nil {
+		return e.with(source, e.Sink), law
+	}
+	sink, law := rewrite(e.Sink)
+	if sink != nil {
+		return e.with(e.Source, sink), law
+	}
+	return nil, ""
+}
+
+func rewriteRoot(e *proofExpr) (*proofExpr, string) {
+	x, y := e.Source, e.Sink
+	switch e.Operation {
+	case ARROW:
+		if y.is(ADD) { // x * (y + z) = x * y + x * z
+			return newProofSum(e.with(x, y.Source), e.with(x, y.Sink)), LawLeftDistributivity
+		}
+		if x.is(ADD) { // (x + y) * z = x * z + y * z
+			return newProofSum(e.with(x.Source, y), e.with(x.Sink, y)), LawRightDistributivity
+		}
+		if x.isIdentityLeaf() && y.identityNeutral() {
+			return y, LawLeftIdentity
+		}
+		if y.isIdentityLeaf() && x.identityNeutral() {
+			return x, LawRightIdentity
+		}
+		if x.is(ARROW) { // (x * y) * z = x * (y * z)
+			return x.with(x.Source, e.with(x.Sink, y)), LawAssociativityOfArrow
+		}
+	case ADD:
+		if y.isZeroLeaf() && !x.isZeroLeaf() {
+			return x, LawZero
+		}
+		if x.isZeroLeaf() && !y.isZeroLeaf() {
+			return y, LawZero
+		}
+		if x.is(ADD) { // (x + y) + z = x + (y + z)
+			return newProofSum(x.Source, newProofSum(x.Sink, y)), LawAssociativityOfAdd
+		}
+		if x.String() == y.String() && !x.isZeroLeaf() {
+			return x, LawIdempotenceOfAdd
+		}
+		if y.is(ADD) {
+			if x.String() == y.Source.String() && !x.isZeroLeaf() { // x + (x + z) = x + z
+				return y, LawIdempotenceOfAdd
+			}
+			if y.Source.String() < x.String() { // x + (y + z) = y + (x + z)
+				return newProofSum(y.Source, newProofSum(x, y.Sink)), LawLeftCommutativityOfAdd
+			}
+		} else if y.String() < x.String() {
+			return newProofSum(y, x), LawCommutativityOfAdd
+		}
+	}
+	return nil, ""
+}
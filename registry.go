@@ -0,0 +1,190 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// OperatorRegistry maps the operator ids back to the Operator instances. It is safe for concurrent use
+type OperatorRegistry interface {
+	// Register adds the operator by its id. Registering another operator with the same id is an error
+	Register(operator Operator) error
+	// Alias makes the operator with the given id to be found also with the alias
+	Alias(alias string, id string) error
+	// Lookup finds an operator by its id or alias
+	Lookup(id string) (Operator, bool)
+	// List returns the registered operators sorted by their ids
+	List() []Operator
+}
+
+// ConnectableRegistry maps the connectable ids back to the Connectable instances. It is safe for concurrent use
+type ConnectableRegistry interface {
+	// Register adds the connectable by its id. Registering another connectable with the same id is an error
+	Register(connectable Connectable) error
+	// Alias makes the connectable with the given id to be found also with the alias
+	Alias(alias string, id string) error
+	// Lookup finds a connectable by its id or alias
+	Lookup(id string) (Connectable, bool)
+	// List returns the registered connectables sorted by their ids
+	List() []Connectable
+}
+
+// NewOperatorRegistry creates a new empty OperatorRegistry
+func NewOperatorRegistry() OperatorRegistry {
+	return &operatorRegistry{registry: newRegistry("operator")}
+}
+
+// NewConnectableRegistry creates a new empty ConnectableRegistry
+func NewConnectableRegistry() ConnectableRegistry {
+	return &connectableRegistry{registry: newRegistry("connectable")}
+}
+
+// implementation details
+
+type registry struct {
+	Kind    string
+	lock    sync.RWMutex
+	Items   map[string]interface{}
+	Aliases map[string]string
+}
+
+func newRegistry(kind string) *registry {
+	return &registry{
+		Kind:    kind,
+		Items:   make(map[string]interface{}),
+		Aliases: make(map[string]string)}
+}
+
+func (r *registry) register(id string, item interface{}) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, isAlias := r.Aliases[id]
+	if isAlias {
+		return fmt.Errorf("The %s id '%s' is already used as an alias", r.Kind, id)
+	}
+	existing, found := r.Items[id]
+	if found && !sameValue(existing, item) {
+		return fmt.Errorf("Another %s is already registered with id '%s'", r.Kind, id)
+	}
+	r.Items[id] = item
+	return nil
+}
+
+func (r *registry) alias(alias string, id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, found := r.Items[id]
+	if !found {
+		return fmt.Errorf("No %s registered with id '%s'", r.Kind, id)
+	}
+	_, found = r.Items[alias]
+	if found {
+		return fmt.Errorf("The alias '%s' is already used as a %s id", alias, r.Kind)
+	}
+	existing, found := r.Aliases[alias]
+	if found && existing != id {
+		return fmt.Errorf("The alias '%s' is already used for the %s '%s'", alias, r.Kind, existing)
+	}
+	r.Aliases[alias] = id
+	return nil
+}
+
+func (r *registry) lookup(id string) (interface{}, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	target, isAlias := r.Aliases[id]
+	if isAlias {
+		id = target
+	}
+	item, found := r.Items[id]
+	return item, found
+}
+
+func (r *registry) sortedIds() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	ids := make([]string, 0, len(r.Items))
+	for k := range r.Items {
+		ids = append(ids, k)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+type operatorRegistry struct {
+	registry *registry
+}
+
+func (r *operatorRegistry) Register(operator Operator) error {
+	if operator == nil {
+		return fmt.Errorf("Registering nil operator")
+	}
+	return r.registry.register(operator.GetId(), operator)
+}
+
+func (r *operatorRegistry) Alias(alias string, id string) error {
+	return r.registry.alias(alias, id)
+}
+
+func (r *operatorRegistry) Lookup(id string) (Operator, bool) {
+	item, found := r.registry.lookup(id)
+	if !found {
+		return nil, false
+	}
+	return item.(Operator), true
+}
+
+func (r *operatorRegistry) List() []Operator {
+	operators := []Operator{}
+	for _, id := range r.registry.sortedIds() {
+		operator, found := r.Lookup(id)
+		if found {
+			operators = append(operators, operator)
+		}
+	}
+	return operators
+}
+
+type connectableRegistry struct {
+	registry *registry
+}
+
+func (r *connectableRegistry) Register(connectable Connectable) error {
+	if connectable == nil {
+		return fmt.Errorf("Registering nil connectable")
+	}
+	return r.registry.register(connectable.GetId(), connectable)
+}
+
+func (r *connectableRegistry) Alias(alias string, id string) error {
+	return r.registry.alias(alias, id)
+}
+
+func (r *connectableRegistry) Lookup(id string) (Connectable, bool) {
+	item, found := r.registry.lookup(id)
+	if !found {
+		return nil, false
+	}
+	return item.(Connectable), true
+}
+
+func (r *connectableRegistry) List() []Connectable {
+	connectables := []Connectable{}
+	for _, id := range r.registry.sortedIds() {
+		connectable, found := r.Lookup(id)
+		if found {
+			connectables = append(connectables, connectable)
+		}
+	}
+	return connectables
+}
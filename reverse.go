@@ -0,0 +1,76 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+// Reverse returns the dual of the given term: every planned connection operation is flipped,
+// sources and sinks are swapped and the processed term tree is mirrored so that
+// '(a + b) * c' becomes 'c * (a + b)'
+func Reverse(term EquationTerm) EquationTerm {
+	return reverseCategory(term)
+}
+
+// ReverseOperation returns a new FreezedOperation connecting the sink of the given operation to its source
+func ReverseOperation(op FreezedOperation) FreezedOperation {
+	freezed, ok := op.(*freezedOperation)
+	if !ok {
+		return withAttributes(NewFreezedOperation(op.GetOperator(), op.GetSink(), op.GetSource()), GetAttributes(op))
+	}
+	reversed := *freezed
+	reversed.Source, reversed.Sink = freezed.Sink, freezed.Source
+	return &reversed
+}
+
+// implementation details
+
+func reverseCategory(c Category) EquationTerm {
+	named, isNamed := c.(NamedTerm)
+	if isNamed {
+		return reverseCategory(named.GetDefinition())
+	}
+	variable, isVariable := c.(VariableTerm)
+	if isVariable {
+		return withPolicy(
+			newVariableTerm(variable.GetOperator(), variable.GetName(), !variable.IsReversed()), policyOf(variable))
+	}
+	encapsulated, isComposite := compositeOf(c)
+	if isComposite {
+		return Encapsulate(encapsulated.GetId(), Reverse(encapsulated.GetTerm()))
+	}
+	term, isTerm := c.(EquationTerm)
+	if isTerm && term.GetProcessedTerm() != nil {
+		processed := term.GetProcessedTerm()
+		source := reverseCategory(processed.GetSource())
+		sink := reverseCategory(processed.GetSink())
+		if processed.GetOperation() == ARROW {
+			return connectTerms(sink, processed.GetOperator(), processed.GetAttributes(), source)
+		}
+		return applyOperation(source, processed, sink)
+	}
+	return reverseLeaf(c)
+}
+
+func reverseLeaf(c Category) EquationTerm {
+	operations := newOperationSet(c.GetOperator(), policyOf(c))
+	for _, op := range c.GetOperations().AsArray() {
+		operations.Add(ReverseOperation(op))
+	}
+
+	stringImpl := func(*categoryImpl) string { return c.String() }
+	if impl, ok := c.(*equationTerm); ok {
+		stringImpl = impl.stringImpl
+	}
+
+	return markStrict(&equationTerm{
+		categoryImpl: categoryImpl{
+			Sources:    c.GetSinks().Clone(),
+			Sinks:      c.GetSources().Clone(),
+			Operator:   c.GetOperator(),
+			Operations: operations,
+			isZero:     c.IsZero(),
+			isIdentity: c.IsIdentity(),
+			stringImpl: stringImpl},
+		processedTerm: nil}, isStrict(c))
+}
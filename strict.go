@@ -0,0 +1,118 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+)
+
+// OperatorMismatchError is returned when the operators of the combined terms are not compatible
+type OperatorMismatchError struct {
+	// Expected is the operator id of the term the operation was done on
+	Expected string
+	// Got is the operator id of the offending term
+	Got string
+	// Term is the offending sub-term, nil when not known
+	Term Category
+}
+
+// Error describes the mismatch
+func (e *OperatorMismatchError) Error() string {
+	if e.Term == nil {
+		return fmt.Sprintf("Expected operator %s, got %s", e.Expected, e.Got)
+	}
+	return fmt.Sprintf("Expected operator %s, got %s on %s", e.Expected, e.Got, e.Term)
+}
+
+// TryAdd is the '+' of the equation operations returning an *OperatorMismatchError on incompatible operators
+func TryAdd(term EquationTerm, category Category) (EquationTerm, error) {
+	err := checkCompatible(term, category)
+	if err != nil {
+		return nil, err
+	}
+	return term.Add(category), nil
+}
+
+// TryDiscard is the '-' of the equation operations returning an *OperatorMismatchError on incompatible operators
+func TryDiscard(term EquationTerm, category Category) (EquationTerm, error) {
+	err := checkCompatible(term, category)
+	if err != nil {
+		return nil, err
+	}
+	return term.Discard(category), nil
+}
+
+// TryConnect is the '->' of the equation operations returning an *OperatorMismatchError on incompatible operators
+func TryConnect(term EquationTerm, category Category) (EquationTerm, error) {
+	err := checkCompatible(term, category)
+	if err != nil {
+		return nil, err
+	}
+	return term.Connect(category), nil
+}
+
+// TryConnectWith is the '->' of the equation operations with the given operator returning
+// an *OperatorMismatchError when the default operators of the terms are incompatible
+func TryConnectWith(term EquationTerm, operator Operator, category Category) (EquationTerm, error) {
+	err := checkCompatible(term, category)
+	if err != nil {
+		return nil, err
+	}
+	return term.ConnectWith(operator, category), nil
+}
+
+// implementation details
+
+type strictChecked interface {
+	isStrict() bool
+}
+
+func (n *namedTerm) isStrict() bool {
+	return isStrict(n.Lookup())
+}
+
+func isStrict(c Category) bool {
+	checked, ok := c.(strictChecked)
+	return ok && checked.isStrict()
+}
+
+func markStrict(term EquationTerm, strict bool) EquationTerm {
+	switch t := term.(type) {
+	case *equationTerm:
+		t.strict = strict
+	case *variableTerm:
+		t.strict = strict
+	}
+	return term
+}
+
+// checkStrict panics with *OperatorMismatchError, if either of the operands is strict and
+// the operators are not compatible
+func checkStrict(term EquationTerm, category Category) {
+	if !isStrict(term) && !isStrict(category) {
+		return
+	}
+	err := checkCompatible(term, category)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func checkCompatible(term EquationTerm, category Category) error {
+	err := CompatibleOperators(term.GetOperator(), category.GetOperator())
+	if err == nil {
+		set, ok := term.GetOperations().(*operationSet)
+		if ok {
+			err = set.CompatibleWithSet(category.GetOperations())
+		}
+	}
+	if err != nil {
+		mismatch := err.(*OperatorMismatchError)
+		mismatch.Term = category
+		return mismatch
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TemplateBody builds the equation of a template from the bound parameters
+type TemplateBody func(G EquationFactory, args map[string]EquationTerm) EquationTerm
+
+// Template is a reusable equation pattern with named parameters, for example
+// 'fanin(workers, collector) = workers * collector'
+type Template interface {
+	// GetName returns the name of the template
+	GetName() string
+	// GetParameters returns the parameter names in their declaration order
+	GetParameters() []string
+	// Instantiate binds the parameters and builds the equation.
+	// Missing and extra bindings and operators differing from the factory's operator are errors
+	Instantiate(bindings map[string]Category) (EquationTerm, error)
+	// String prints the template signature in human readable form
+	String() string
+}
+
+// NewTemplate creates a new template whose equation is built by the given Go function.
+// The names are checked like on ParseTemplate, because the template may be called from the textual form
+func NewTemplate(factory EquationFactory, name string, parameters []string, body TemplateBody) (Template, error) {
+	err := checkTemplateNames(name, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	build := func(G EquationFactory, args map[string]EquationTerm) (EquationTerm, error) {
+		return body(G, args), nil
+	}
+	return newTemplate(factory, name, parameters, build), nil
+}
+
+// ParseTemplate creates a new template from the textual equation form, for example 'workers * collector'.
+// The parameters must be identifiers of the textual form other than the reserved 'I' and 'O'.
+// The templates are instantiated from the textual form with ParseWithTemplates
+func ParseTemplate(factory EquationFactory, name string, parameters []string, text string) (Template, error) {
+	err := checkTemplateNames(name, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	check := make(map[string]Category, len(parameters))
+	for _, parameter := range parameters {
+		check[parameter] = factory.I()
+	}
+	_, err = Parse(factory, text, check)
+	if err != nil {
+		return nil, err
+	}
+
+	build := func(G EquationFactory, args map[string]EquationTerm) (EquationTerm, error) {
+		names := make(map[string]Category, len(args))
+		for k, v := range args {
+			names[k] = v
+		}
+		return Parse(G, text, names)
+	}
+	return newTemplate(factory, name, parameters, build), nil
+}
+
+// implementation details
+
+type template struct {
+	Factory    EquationFactory
+	Name       string
+	Parameters []string
+	Build      func(G EquationFactory, args map[string]EquationTerm) (EquationTerm, error)
+}
+
+func newTemplate(
+	factory EquationFactory,
+	name string,
+	parameters []string,
+	build func(G EquationFactory, args map[string]EquationTerm) (EquationTerm, error)) Template {
+	return &template{
+		Factory:    factory,
+		Name:       name,
+		Parameters: append([]string{}, parameters...),
+		Build:      build}
+}
+
+// checkTemplateNames returns an error, if the names can not be used on the textual form
+func checkTemplateNames(name string, parameters []string) error {
+	if !isIdentifier(name) {
+		return fmt.Errorf("Invalid template name '%s'", name)
+	}
+	seen := make(map[string]bool, len(parameters))
+	for _, parameter := range parameters {
+		if !isIdentifier(parameter) {
+			return fmt.Errorf("Template %s has invalid parameter name '%s'", name, parameter)
+		}
+		if parameter == "I" || parameter == "O" {
+			return fmt.Errorf("Template %s parameter name '%s' is reserved", name, parameter)
+		}
+		if seen[parameter] {
+			return fmt.Errorf("Template %s has duplicate parameter '%s'", name, parameter)
+		}
+		seen[parameter] = true
+	}
+	return nil
+}
+
+func (t *template) GetName() string {
+	return t.Name
+}
+
+func (t *template) GetParameters() []string {
+	return append([]string{}, t.Parameters...)
+}
+
+func (t *template) Instantiate(bindings map[string]Category) (EquationTerm, error) {
+	missing := []string{}
+	args := make(map[string]EquationTerm, len(t.Parameters))
+	for _, parameter := range t.Parameters {
+		c, found := bindings[parameter]
+		if !found || c == nil {
+			missing = append(missing, parameter)
+			continue
+		}
+		err := CompatibleOperators(t.Factory.GetOperator(), c.GetOperator())
+		if err != nil {
+			return nil, fmt.Errorf("Template %s parameter %s: %s", t.Name, parameter, err)
+		}
+		args[parameter] = toTerm(c)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Template %s is missing bindings: %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	extra := []string{}
+	for k := range bindings {
+		_, found := args[k]
+		if !found {
+			extra = append(extra, k)
+		}
+	}
+	if len(extra) > 0 {
+		sort.Strings(extra)
+		return nil, fmt.Errorf("Template %s has extra bindings: %s", t.Name, strings.Join(extra, ", "))
+	}
+
+	term, err := t.Build(t.Factory, args)
+	if err != nil {
+		return nil, fmt.Errorf("Template %s: %s", t.Name, err)
+	}
+	return term, nil
+}
+
+func (t *template) String() string {
+	return fmt.Sprintf("%s(%s)", t.Name, strings.Join(t.Parameters, ", "))
+}
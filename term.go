@@ -17,6 +17,10 @@ type ProcessedTerm interface {
 	GetOperation() Operation
 	// GetSource returns the source of the done operation
 	GetSource() Category
+	// GetOperator returns the operator used for the new connections of the ARROW operation
+	GetOperator() Operator
+	// GetAttributes returns the attributes attached to the new connections of the ARROW operation or nil
+	GetAttributes() Attributes
 	// Equals returns true, if the operation had the same parameters
 	Equals(another ProcessedTerm) bool
 	// String returns a human readable description of the done operation
@@ -55,20 +59,26 @@ func EqualOperators(f Operator, another Operator) bool {
 	return f.GetId() == another.GetId()
 }
 
-// CompatibleOperators returns an error, if the connection operators are not same
+// CompatibleOperators returns an *OperatorMismatchError, if the connection operators are not same
 func CompatibleOperators(f Operator, another Operator) error {
 	if !EqualOperators(f, another) {
-		return fmt.Errorf("Expected operator %s, got %s", f.GetId(), another.GetId())
+		return &OperatorMismatchError{Expected: f.GetId(), Got: another.GetId()}
 	}
 	return nil
 }
 
-// NewProcessedTerm returns a new ProcessedTerm instance
+// NewProcessedTerm returns a new ProcessedTerm instance using the operator of the source
 func NewProcessedTerm(source Category, operation Operation, sink Category) ProcessedTerm {
+	return NewProcessedTermWithOperator(source, operation, source.GetOperator(), sink)
+}
+
+// NewProcessedTermWithOperator returns a new ProcessedTerm instance
+func NewProcessedTermWithOperator(source Category, operation Operation, operator Operator, sink Category) ProcessedTerm {
 	return &processedTerm{
 		Source:    source,
 		Sink:      sink,
-		Operation: operation}
+		Operation: operation,
+		Operator:  operator}
 }
 
 // NewIdentityTerm returns a new identity term instance
@@ -107,16 +117,20 @@ func NewWrapperTerm(operator Operator, connectable Connectable) EquationTerm {
 	sinks := NewConnectableSet()
 	sinks.Add(connectable)
 
-	return &equationTerm{
-		categoryImpl: categoryImpl{
-			Sources:    sources,
-			Sinks:      sinks,
-			Operator:   operator,
-			Operations: NewOperationSet(operator),
-			isZero:     false,
-			isIdentity: false,
-			stringImpl: func(c *categoryImpl) string { return connectable.GetId() }},
-		processedTerm: nil}
+	return newLeafTerm(operator, sources, sinks, connectable.GetId())
+}
+
+// NewPlannedTerm returns a new term made directly from the planned connection operations, sources and sinks.
+// It is printed by the given name
+func NewPlannedTerm(
+	operator Operator,
+	sources ConnectableSet,
+	sinks ConnectableSet,
+	operations OperationSet,
+	name string) EquationTerm {
+	term := newLeafTerm(operator, sources, sinks, name).(*equationTerm)
+	term.Operations = operations
+	return term
 }
 
 // NewIntermediateTerm returns a new intermediate term. Used on the arithmetic operation implementations
@@ -135,15 +149,57 @@ func NewIntermediateTerm(
 			isZero:     false,
 			isIdentity: processedTerm != nil && processedTerm.GetOperation() == ADD && (processedTerm.GetSink().IsIdentity() || processedTerm.GetSource().IsIdentity()),
 			stringImpl: func(c *categoryImpl) string { return processedTerm.String() }},
-		processedTerm: processedTerm}
+		processedTerm: processedTerm,
+		variables:     processedTerm != nil && (hasVariables(processedTerm.GetSource()) || hasVariables(processedTerm.GetSink()))}
 }
 
 // implementation details
 
+// withPolicy makes the sets of the leaf term use the policies
+func withPolicy(term EquationTerm, policy setPolicy) EquationTerm {
+	var impl *categoryImpl
+	switch t := term.(type) {
+	case *equationTerm:
+		impl = &t.categoryImpl
+	case *variableTerm:
+		impl = &t.categoryImpl
+	default:
+		return term
+	}
+	impl.Sources = newConnectableSetFromArray(policy.Identity, impl.Sources.AsArray())
+	impl.Sinks = newConnectableSetFromArray(policy.Identity, impl.Sinks.AsArray())
+	impl.Operations = newOperationSet(impl.Operations.GetOperator(), policy).Union(impl.Operations)
+	return term
+}
+
+func newLeafTerm(operator Operator, sources ConnectableSet, sinks ConnectableSet, name string) EquationTerm {
+	return &equationTerm{
+		categoryImpl: categoryImpl{
+			Sources:    sources,
+			Sinks:      sinks,
+			Operator:   operator,
+			Operations: NewOperationSet(operator),
+			isZero:     false,
+			isIdentity: false,
+			stringImpl: func(c *categoryImpl) string { return name }},
+		processedTerm: nil}
+}
+
 type processedTerm struct {
-	Sink      Category
-	Source    Category
-	Operation Operation
+	Sink       Category
+	Source     Category
+	Operation  Operation
+	Operator   Operator
+	Attributes Attributes
+}
+
+func newConnectProcessedTerm(source Category, operator Operator, attributes Attributes, sink Category) ProcessedTerm {
+	return &processedTerm{
+		Source:     source,
+		Sink:       sink,
+		Operation:  ARROW,
+		Operator:   operator,
+		Attributes: attributes.Clone()}
 }
 
 func (p *processedTerm) GetSink() Category {
@@ -159,68 +215,180 @@ func (p *processedTerm) GetSource() Category {
 
 }
 
+func (p *processedTerm) GetOperator() Operator {
+	return p.Operator
+}
+
+func (p *processedTerm) GetAttributes() Attributes {
+	return p.Attributes
+}
+
 func (p *processedTerm) Equals(another ProcessedTerm) bool {
-	return p.Sink.Equals(another.GetSink()) && p.Source.Equals(another.GetSource()) && p.Operation == another.GetOperation()
+	return p.Sink.Equals(another.GetSink()) && p.Source.Equals(another.GetSource()) && p.Operation == another.GetOperation() &&
+		EqualOperators(p.Operator, another.GetOperator())
 }
 
 func (p *processedTerm) String() string {
-	return fmt.Sprintf("(%s) %s (%s)", p.Source.String(), O2S(p.Operation), p.Sink.String())
+	return formatOperation(p.Source.String(), p.Operation, p.Operator, p.Source.GetOperator(), p.Attributes, p.Sink.String())
+}
+
+// formatOperation prints the operation, the operator id is shown only when it differs from the default one
+// and the attributes only when there are some: '(a) *[control]{weight=2} (b)'
+func formatOperation(
+	source string, operation Operation, operator Operator, defaultOperator Operator, attributes Attributes, sink string) string {
+	symbol := O2S(operation)
+	if operation == ARROW && !EqualOperators(operator, defaultOperator) {
+		symbol = symbol + "[" + operator.GetId() + "]"
+	}
+	if operation == ARROW && len(attributes) > 0 {
+		symbol = symbol + attributes.String()
+	}
+	return fmt.Sprintf("(%s) %s (%s)", source, symbol, sink)
 }
 
 type equationTerm struct {
 	categoryImpl
 	processedTerm ProcessedTerm
+	strict        bool
+	// variables tells if the processed term tree contains variables. It is set when the term is built
+	// to keep the evaluation checks independent of the size of the tree
+	variables bool
+}
+
+func (e *equationTerm) isStrict() bool {
+	return e.strict
 }
 
 func (e *equationTerm) GetProcessedTerm() ProcessedTerm {
 	return e.processedTerm
 }
 
+func (e *equationTerm) Evaluate() error {
+	err := checkBound(e)
+	if err != nil {
+		return err
+	}
+	return e.categoryImpl.Evaluate()
+}
+
+func (e *equationTerm) EvaluateSorted() error {
+	err := checkBound(e)
+	if err != nil {
+		return err
+	}
+	return e.categoryImpl.EvaluateSorted()
+}
+
+func (e *equationTerm) Teardown() error {
+	err := checkBound(e)
+	if err != nil {
+		return err
+	}
+	return e.categoryImpl.Teardown()
+}
+
 func (e *equationTerm) Add(category Category) EquationTerm {
-	return NewIntermediateTerm(
-		e.Operator,
-		e.Sources.Union(category.GetSources()),
-		e.Sinks.Union(category.GetSinks()),
-		e.Operations.Union(category.GetOperations()),
-		NewProcessedTerm(e, ADD, category))
+	return addTerms(e, category)
 }
 
 func (e *equationTerm) Discard(category Category) EquationTerm {
-	return NewIntermediateTerm(
-		e.Operator,
-		e.Sources.DiscardAll(category.GetSources()),
-		e.Sinks.DiscardAll(category.GetSinks()),
-		e.Operations.DiscardAll(category.GetOperations()),
-		NewProcessedTerm(e, DISCARD, category))
+	return discardTerms(e, category)
 }
 
 func (e *equationTerm) Connect(anext Category) EquationTerm {
+	return connectTerms(e, e.Operator, nil, anext)
+}
+
+func (e *equationTerm) ConnectWith(operator Operator, anext Category) EquationTerm {
+	return connectTerms(e, operator, nil, anext)
+}
+
+func (e *equationTerm) ConnectWithAttributes(attributes Attributes, anext Category) EquationTerm {
+	return connectTerms(e, e.Operator, attributes, anext)
+}
+
+// applyOperation redoes the processed arithmetic operation for new operands: used when rebuilding processed term trees
+func applyOperation(source EquationTerm, processed ProcessedTerm, sink Category) EquationTerm {
+	switch processed.GetOperation() {
+	case ADD:
+		return source.Add(sink)
+	case DISCARD:
+		return source.Discard(sink)
+	case ARROW:
+		return connectTerms(source, processed.GetOperator(), processed.GetAttributes(), sink)
+	}
+	panic("invalid operation")
+}
+
+// toTerm returns the category as an equation term, wrapping it when needed
+func toTerm(c Category) EquationTerm {
+	term, isTerm := c.(EquationTerm)
+	if isTerm {
+		return term
+	}
+	return &equationTerm{
+		categoryImpl: categoryImpl{
+			Sources:    c.GetSources().Clone(),
+			Sinks:      c.GetSinks().Clone(),
+			Operator:   c.GetOperator(),
+			Operations: c.GetOperations().Clone(),
+			isZero:     c.IsZero(),
+			isIdentity: c.IsIdentity(),
+			stringImpl: func(*categoryImpl) string { return c.String() }},
+		processedTerm: nil}
+}
+
+func addTerms(e EquationTerm, category Category) EquationTerm {
+	checkStrict(e, category)
+	return markStrict(NewIntermediateTerm(
+		e.GetOperator(),
+		e.GetSources().Union(category.GetSources()),
+		e.GetSinks().Union(category.GetSinks()),
+		e.GetOperations().Union(category.GetOperations()),
+		NewProcessedTerm(e, ADD, category)), isStrict(e) || isStrict(category))
+}
+
+func discardTerms(e EquationTerm, category Category) EquationTerm {
+	checkStrict(e, category)
+	return markStrict(NewIntermediateTerm(
+		e.GetOperator(),
+		e.GetSources().DiscardAll(category.GetSources()),
+		e.GetSinks().DiscardAll(category.GetSinks()),
+		e.GetOperations().DiscardAll(category.GetOperations()),
+		NewProcessedTerm(e, DISCARD, category)), isStrict(e) || isStrict(category))
+}
+
+// connectTerms connects the sources of e to the sinks of anext using the operator and the attributes.
+// The result has the operator of e like the results of the other operations
+func connectTerms(e EquationTerm, operator Operator, attributes Attributes, anext Category) EquationTerm {
+	checkStrict(e, anext)
+	policy := policyOf(e)
 	if e.IsZero() {
-		return NewIntermediateTerm(
-			anext.GetOperator(),
-			anext.GetSources().Clone(),
-			NewConnectableSet(),
-			anext.GetOperations().Clone(),
-			NewProcessedTerm(e, ARROW, anext))
+		return markStrict(NewIntermediateTerm(
+			e.GetOperator(),
+			newConnectableSetFromArray(policy.Identity, anext.GetSources().AsArray()),
+			newConnectableSetFromArray(policy.Identity, []Connectable{}),
+			newOperationSet(anext.GetOperations().GetOperator(), policy).Union(anext.GetOperations()),
+			newConnectProcessedTerm(e, operator, attributes, anext)), isStrict(e) || isStrict(anext))
 	}
 	if anext.IsZero() {
-		return NewIntermediateTerm(
-			anext.GetOperator(),
-			NewConnectableSet(),
+		return markStrict(NewIntermediateTerm(
+			e.GetOperator(),
+			newConnectableSetFromArray(policy.Identity, []Connectable{}),
 			e.GetSinks().Clone(),
-			e.Operations.Clone(),
-			NewProcessedTerm(e, ARROW, anext))
+			e.GetOperations().Clone(),
+			newConnectProcessedTerm(e, operator, attributes, anext)), isStrict(e) || isStrict(anext))
 	}
 
-	newOperations := NewOperationSet(e.Operator)
+	newOperations := newOperationSet(operator, policy)
 
-	for _, source := range e.Sources.AsArray() {
+	for _, source := range e.GetSources().AsArray() {
 		for _, sink := range anext.GetSinks().AsArray() {
-			newOperations.Add(NewFreezedOperation(e.Operator, source, sink))
+			newOperations.Add(withAttributes(&freezedOperation{Source: source, Sink: sink, Operator: operator, Equaler: policy.Identity}, attributes))
 		}
 	}
 
-	newSources := NewConnectableSet()
+	newSources := newConnectableSetFromArray(policy.Identity, []Connectable{})
 	for _, source := range anext.GetSources().AsArray() {
 		newSources.Add(source)
 	}
@@ -230,7 +398,7 @@ func (e *equationTerm) Connect(anext Category) EquationTerm {
 		}
 	}
 
-	newSinks := NewConnectableSet()
+	newSinks := newConnectableSetFromArray(policy.Identity, []Connectable{})
 	for _, sink := range e.GetSinks().AsArray() {
 		newSinks.Add(sink)
 	}
@@ -241,12 +409,12 @@ func (e *equationTerm) Connect(anext Category) EquationTerm {
 		}
 	}
 
-	operations := e.Operations.Union(anext.GetOperations()).Union(newOperations)
+	operations := e.GetOperations().Union(anext.GetOperations()).Union(newOperations)
 
-	return NewIntermediateTerm(
-		anext.GetOperator(),
+	return markStrict(NewIntermediateTerm(
+		e.GetOperator(),
 		newSources,
 		newSinks,
 		operations,
-		NewProcessedTerm(e, ARROW, anext))
+		newConnectProcessedTerm(e, operator, attributes, anext)), isStrict(e) || isStrict(anext))
 }
@@ -0,0 +1,217 @@
+//
+// @copyright: 2019 by Pauli Rikula <pauli.rikula@gmail.com>
+// @license: MIT <http://www.opensource.org/licenses/mit-license.php>
+//
+
+package category
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VariableTerm is a free variable placeholder for a part of the equation which is known only later.
+// It has no sources, sinks or operations of its own: the arithmetic operations done with it are
+// recorded on the processed term tree and computed when the variable is bound.
+// Evaluating or tearing down a term having free variables returns an error naming them
+type VariableTerm interface {
+	EquationTerm
+	// GetName returns the name of the variable
+	GetName() string
+	// IsReversed tells if the bound term should be reversed, see Reverse
+	IsReversed() bool
+}
+
+// NewVariableTerm returns a new free variable term instance
+func NewVariableTerm(operator Operator, name string) EquationTerm {
+	return newVariableTerm(operator, name, false)
+}
+
+// FreeVars returns the sorted names of the free variables of the term
+func FreeVars(term EquationTerm) []string {
+	found := make(map[string]bool)
+	collectFreeVars(term, found, make(map[*equationTerm]bool))
+
+	names := make([]string, 0, len(found))
+	for k := range found {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Bind replaces the free variables with the bound categories and recomputes the operations.
+// Variables without a binding are left free
+func Bind(term EquationTerm, bindings map[string]Category) (EquationTerm, error) {
+	for name, c := range bindings {
+		if c == nil {
+			return nil, fmt.Errorf("Variable %s bound to nil", name)
+		}
+		err := CompatibleOperators(term.GetOperator(), c.GetOperator())
+		if err != nil {
+			return nil, fmt.Errorf("Variable %s: %s", name, err)
+		}
+	}
+	return bindTerm(term, bindings), nil
+}
+
+// implementation details
+
+func newVariableTerm(operator Operator, name string, reversed bool) *variableTerm {
+	printed := "$" + name
+	if reversed {
+		printed = "~" + printed
+	}
+	return &variableTerm{
+		equationTerm: equationTerm{
+			categoryImpl: categoryImpl{
+				Sources:    NewConnectableSet(),
+				Sinks:      NewConnectableSet(),
+				Operator:   operator,
+				Operations: NewOperationSet(operator),
+				isZero:     false,
+				isIdentity: false,
+				stringImpl: func(c *categoryImpl) string { return printed }},
+			processedTerm: nil},
+		Name:     name,
+		Reversed: reversed}
+}
+
+// checkBound returns an error naming the free variables of the term, because the operations
+// of a term having free variables are not known yet
+func checkBound(term EquationTerm) error {
+	if !hasVariables(term) {
+		return nil
+	}
+	return fmt.Errorf("Unbound variables: %s", strings.Join(FreeVars(term), ", "))
+}
+
+type variableChecked interface {
+	hasVariables() bool
+}
+
+func (e *equationTerm) hasVariables() bool {
+	return e.variables
+}
+
+func (v *variableTerm) hasVariables() bool {
+	return true
+}
+
+func (n *namedTerm) hasVariables() bool {
+	return hasVariables(n.Lookup())
+}
+
+// hasVariables tells if the term contains variables without walking its processed term tree.
+// The terms made outside of this package are walked
+func hasVariables(c Category) bool {
+	checked, ok := c.(variableChecked)
+	if ok {
+		return checked.hasVariables()
+	}
+	term, isTerm := c.(EquationTerm)
+	if !isTerm || term.GetProcessedTerm() == nil {
+		return false
+	}
+	return hasVariables(term.GetProcessedTerm().GetSource()) || hasVariables(term.GetProcessedTerm().GetSink())
+}
+
+// collectFreeVars walks only the subterms having variables and each of them once
+func collectFreeVars(c Category, found map[string]bool, visited map[*equationTerm]bool) {
+	if !hasVariables(c) {
+		return
+	}
+	impl, isImpl := c.(*equationTerm)
+	if isImpl {
+		if visited[impl] {
+			return
+		}
+		visited[impl] = true
+	}
+
+	switch term := c.(type) {
+	case VariableTerm:
+		found[term.GetName()] = true
+	case NamedTerm:
+		collectFreeVars(term.GetDefinition(), found, visited)
+	case EquationTerm:
+		processed := term.GetProcessedTerm()
+		if processed != nil {
+			collectFreeVars(processed.GetSource(), found, visited)
+			collectFreeVars(processed.GetSink(), found, visited)
+		}
+	}
+}
+
+func bindTerm(term EquationTerm, bindings map[string]Category) EquationTerm {
+	if !hasVariables(term) {
+		return term
+	}
+
+	switch t := term.(type) {
+	case VariableTerm:
+		c, found := bindings[t.GetName()]
+		if !found {
+			return t
+		}
+		if t.IsReversed() {
+			return Reverse(toTerm(c))
+		}
+		return toTerm(c)
+	case NamedTerm:
+		return bindTerm(t.GetDefinition(), bindings)
+	}
+
+	processed := term.GetProcessedTerm()
+	return applyOperation(
+		bindTerm(toTerm(processed.GetSource()), bindings),
+		processed,
+		bindTerm(toTerm(processed.GetSink()), bindings))
+}
+
+type variableTerm struct {
+	equationTerm
+	Name     string
+	Reversed bool
+}
+
+func (v *variableTerm) GetName() string {
+	return v.Name
+}
+
+func (v *variableTerm) IsReversed() bool {
+	return v.Reversed
+}
+
+func (v *variableTerm) Evaluate() error {
+	return checkBound(v)
+}
+
+func (v *variableTerm) EvaluateSorted() error {
+	return checkBound(v)
+}
+
+func (v *variableTerm) Teardown() error {
+	return checkBound(v)
+}
+
+func (v *variableTerm) Add(category Category) EquationTerm {
+	return addTerms(v, category)
+}
+
+func (v *variableTerm) Discard(category Category) EquationTerm {
+	return discardTerms(v, category)
+}
+
+func (v *variableTerm) Connect(anext Category) EquationTerm {
+	return connectTerms(v, v.Operator, nil, anext)
+}
+
+func (v *variableTerm) ConnectWith(operator Operator, anext Category) EquationTerm {
+	return connectTerms(v, operator, nil, anext)
+}
+
+func (v *variableTerm) ConnectWithAttributes(attributes Attributes, anext Category) EquationTerm {
+	return connectTerms(v, v.GetOperator(), attributes, anext)
+}